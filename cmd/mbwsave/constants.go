@@ -485,6 +485,45 @@ const (
 	_NobleRefugees
 )
 
+// NativeFactionStringIds maps the string ids of Native's factions to their
+// ids, for modules without a factions.txt at hand.
+var NativeFactionStringIds = map[string]int{
+	"fac_no_faction":                NoFaction,
+	"fac_commoners":                 Commoners,
+	"fac_outlaws":                   Outlaws,
+	"fac_neutral":                   Neutral,
+	"fac_innocents":                 Innocents,
+	"fac_merchants":                 Merchants,
+	"fac_player_faction":            PlayerFaction,
+	"fac_player_supporters_faction": PlayersSupporters,
+	"fac_kingdom_1":                 KingdomOfSwadia,
+	"fac_kingdom_2":                 KingdomOfVaegirs,
+	"fac_kingdom_3":                 KhergitKhanate,
+	"fac_kingdom_4":                 KingdomOfNords,
+	"fac_kingdom_5":                 KingdomOfRhodoks,
+	"fac_kingdom_6":                 SarranidSultanate,
+	"fac_manhunters":                Manhunters,
+	"fac_deserters":                 Deserters,
+	"fac_mountain_bandits":          MountainBandits,
+	"fac_forest_bandits":            ForestBandits,
+}
+
+var KingdomIds = []int{
+	KingdomOfSwadia,
+	KingdomOfVaegirs,
+	KhergitKhanate,
+	KingdomOfNords,
+	KingdomOfRhodoks,
+	SarranidSultanate,
+}
+
+const (
+	// See slot_party_type, spt_.* in module_constants.py
+	PartyTypeCastle = 2 + iota
+	PartyTypeTown
+	PartyTypeVillage
+)
+
 const (
 	// See slot_village_state, svs_.* in module_constants.py
 	VillageNormal = iota
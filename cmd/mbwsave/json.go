@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/analyticdan/mbw-savegame-editor/savegame"
+)
+
+// ExportOptions adds views of the game decoded with the names of the module
+// of its LoadOptions. ImportFromJson cannot map them back and rejects them.
+type ExportOptions struct {
+	savegame.LoadOptions
+	NamedGlobalVariables bool
+	// TroopSkills adds the skills of each troop that has any, keyed by troop id.
+	TroopSkills bool
+}
+
+type jsonExport struct {
+	savegame.Game
+	NamedGlobalVariables map[string]savegame.Int64 `json:",omitempty"`
+	TroopSkills          map[int]map[string]int    `json:",omitempty"`
+}
+
+func ExportToJson(game savegame.Game, path string, options ExportOptions) (err error) {
+	export := jsonExport{Game: game}
+	if options.NamedGlobalVariables {
+		export.NamedGlobalVariables = game.NamedGlobalVars(options.LoadOptions)
+	}
+	if options.TroopSkills {
+		export.TroopSkills = make(map[int]map[string]int)
+		for troopId, troop := range game.Troops {
+			if skills := troop.NamedSkills(options.LoadOptions); len(skills) > 0 {
+				export.TroopSkills[troopId] = skills
+			}
+		}
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "    ")
+	err = encoder.Encode(export)
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// ImportFromJson reads a game written by ExportToJson without named views,
+// which would otherwise be silently dropped. The Num.* fields are recomputed
+// from the lists they describe, so lists can be edited freely.
+func ImportFromJson(path string) (game savegame.Game, err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return game, err
+	}
+	defer in.Close()
+	var export jsonExport
+	err = json.NewDecoder(in).Decode(&export)
+	if err != nil {
+		return game, fmt.Errorf("%s: %w", path, err)
+	}
+	if export.NamedGlobalVariables != nil || export.TroopSkills != nil {
+		return game, fmt.Errorf("%s: exported with -named, whose named fields cannot be imported; export it again without -named", path)
+	}
+	game = export.Game
+	game.SyncCounts()
+	return game, nil
+}
+
+func PrintJson(gameObject any) {
+	bytes, err := json.MarshalIndent(gameObject, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(bytes))
+}
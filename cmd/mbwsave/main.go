@@ -0,0 +1,307 @@
+// Command mbwsave inspects and edits Mount and Blade Warband savegames.
+//
+// Usage:
+//
+//	mbwsave [-module dir] <command> [arguments]
+//
+// The -module flag points at the module the savegame was made with, e.g.
+// ".../Mount&Blade Warband/Modules/Native". Without it, Native is assumed.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+	"github.com/analyticdan/mbw-savegame-editor/savegame"
+)
+
+type command struct {
+	usage       string
+	description string
+	run         func(options savegame.LoadOptions, args []string) error
+}
+
+var commands = map[string]command{
+	"info": {
+		usage:       "info <savegame>",
+		description: "print the header and size of a savegame",
+		run:         runInfo,
+	},
+	"diff": {
+		usage:       "diff <old savegame> <new savegame>",
+		description: "print the fields that changed between two savegames",
+		run:         runDiff,
+	},
+	"export": {
+		usage:       "export [-named] <savegame> <json>",
+		description: "write a savegame as JSON, with -named also its global variables and troop skills by name, which import does not accept",
+		run:         runExport,
+	},
+	"get": {
+		usage:       "get <savegame> <path>...",
+		description: "print fields of a savegame by path, e.g. Troops[0].Gold",
+		run:         runGet,
+	},
+	"import": {
+		usage:       "import [-backups n] [-skip-validation] <json> <savegame>",
+		description: "write a savegame from JSON written by export, with -backups keeping n earlier versions",
+		run:         runImport,
+	},
+	"set": {
+		usage:       "set [-backups n] [-skip-validation] <savegame> (<path> <value>)...",
+		description: "set fields of a savegame by path and save it in place, with -skip-validation even if validate finds issues",
+		run:         runSet,
+	},
+	"validate": {
+		usage:       "validate <savegame>",
+		description: "check that a savegame loads, saves back unchanged and refers only to objects that exist",
+		run:         runValidate,
+	},
+	"report": {
+		usage:       "report <name> <savegame> [arguments]",
+		description: "print a report, see below",
+		run:         runReport,
+	},
+}
+
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	moduleDir := flag.String("module", "", "module `directory` the savegame was made with (default: Native)")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	command, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "mbwsave: unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	var options savegame.LoadOptions
+	if *moduleDir != "" {
+		m, err := module.Load(*moduleDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "mbwsave:", err)
+			os.Exit(1)
+		}
+		options.Module = m
+	}
+	err := command.run(options, flag.Args()[1:])
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(os.Stderr, "usage: mbwsave [-module dir]", command.usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "mbwsave:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "usage: mbwsave [-module dir] <command> [arguments]")
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(out, "\nCommands:")
+	for _, name := range sortedKeys(commands) {
+		fmt.Fprintf(out, "  %-40s %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintln(out, "\nReports:")
+	for _, name := range sortedKeys(reports) {
+		fmt.Fprintf(out, "  %-40s %s\n", name, reports[name].description)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func runInfo(options savegame.LoadOptions, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	game, err := savegame.Load(args[0], options)
+	if err != nil {
+		return err
+	}
+	header := game.Header
+	validPartyRecords := 0
+	for _, partyRecord := range game.PartyRecords {
+		if partyRecord.Valid == 1 {
+			validPartyRecords++
+		}
+	}
+	fmt.Printf("Savegame:       %s\n", header.SavegameName)
+	fmt.Printf("Player:         %s (level %d)\n", header.PlayerName, header.PlayerLevel)
+	fmt.Printf("Game version:   %d\n", header.GameVersion)
+	fmt.Printf("Module version: %d\n", header.ModuleVersion)
+	fmt.Printf("Date:           year %d, month %d, day %d, hour %d\n", game.Year, game.Month, game.Day, game.Hour)
+	fmt.Printf("Parties:        %d (%d records)\n", validPartyRecords, len(game.PartyRecords))
+	fmt.Printf("Troops:         %d\n", len(game.Troops))
+	fmt.Printf("Factions:       %d\n", len(game.Factions))
+	fmt.Printf("Quests:         %d\n", len(game.Quests))
+	return nil
+}
+
+func runExport(options savegame.LoadOptions, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	named := flags.Bool("named", false, "")
+	if flags.Parse(args) != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+	exportOptions := ExportOptions{LoadOptions: options, NamedGlobalVariables: *named, TroopSkills: *named}
+	game, err := savegame.Load(flags.Arg(0), options)
+	if err != nil {
+		return err
+	}
+	return ExportToJson(game, flags.Arg(1), exportOptions)
+}
+
+func runImport(options savegame.LoadOptions, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	backups := flags.Int("backups", 0, "")
+	skipValidation := flags.Bool("skip-validation", false, "")
+	if flags.Parse(args) != nil || flags.NArg() != 2 || *backups < 0 {
+		return errUsage
+	}
+	game, err := ImportFromJson(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return save(game, flags.Arg(1), savegame.SaveOptions{LoadOptions: options, SkipValidation: *skipValidation, Backups: *backups})
+}
+
+func runGet(options savegame.LoadOptions, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	game, err := savegame.Load(args[0], options)
+	if err != nil {
+		return err
+	}
+	for _, path := range args[1:] {
+		value, err := savegame.Get(game, path)
+		if err != nil {
+			return err
+		}
+		switch value := value.(type) {
+		case savegame.Bool, savegame.Int32, savegame.Int64, savegame.UInt32, savegame.UInt64, savegame.Float, savegame.String:
+			fmt.Printf("%s: %v\n", path, value)
+		default:
+			fmt.Printf("%s:\n", path)
+			PrintJson(value)
+		}
+	}
+	return nil
+}
+
+func runSet(options savegame.LoadOptions, args []string) error {
+	flags := flag.NewFlagSet("set", flag.ContinueOnError)
+	backups := flags.Int("backups", 0, "")
+	skipValidation := flags.Bool("skip-validation", false, "")
+	if flags.Parse(args) != nil || flags.NArg() < 3 || flags.NArg()%2 != 1 || *backups < 0 {
+		return errUsage
+	}
+	path := flags.Arg(0)
+	game, err := savegame.Load(path, options)
+	if err != nil {
+		return err
+	}
+	edits := flags.Args()[1:]
+	for i := 0; i < len(edits); i += 2 {
+		err = savegame.SetText(&game, edits[i], edits[i+1])
+		if err != nil {
+			return err
+		}
+	}
+	return save(game, path, savegame.SaveOptions{LoadOptions: options, SkipValidation: *skipValidation, Backups: *backups})
+}
+
+// save is savegame.Save, pointing out -skip-validation when it refuses the
+// game.
+func save(game savegame.Game, path string, options savegame.SaveOptions) error {
+	err := savegame.Save(game, path, options)
+	var validationErr *savegame.ValidationError
+	if errors.As(err, &validationErr) {
+		return fmt.Errorf("%w; pass -skip-validation to save anyway", err)
+	}
+	return err
+}
+
+func runValidate(options savegame.LoadOptions, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	game, err := savegame.Decode(bytes.NewReader(data), options)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	err = savegame.Encode(&buf, game, savegame.SaveOptions{LoadOptions: options, SkipValidation: true})
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, buf.Bytes()) {
+		return fmt.Errorf("%s changes when saved back; the -module option may not match the savegame", args[0])
+	}
+	issues := savegame.Validate(game)
+	for _, issue := range issues {
+		fmt.Printf("%s: %s\n", args[0], issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%s has %d issues", args[0], len(issues))
+	}
+	fmt.Printf("%s: ok\n", args[0])
+	return nil
+}
+
+func runDiff(options savegame.LoadOptions, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	oldGame, err := savegame.Load(args[0], options)
+	if err != nil {
+		return err
+	}
+	newGame, err := savegame.Load(args[1], options)
+	if err != nil {
+		return err
+	}
+	for _, change := range savegame.Diff(oldGame, newGame, options) {
+		fmt.Println(change)
+	}
+	return nil
+}
+
+func runReport(options savegame.LoadOptions, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	report, ok := reports[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown report %q, expected one of: %s", args[0], strings.Join(sortedKeys(reports), ", "))
+	}
+	game, err := savegame.Load(args[1], options)
+	if err != nil {
+		return err
+	}
+	return report.run(game, options.Module, args[2:])
+}
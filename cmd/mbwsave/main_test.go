@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/savegame"
+)
+
+// A real savegame is not part of the repository; copy one here to run these
+// tests. The savegame package tests the same round trips on generated games.
+const inPath = "sg05.sav"
+
+func requireSavegame(t *testing.T) (outPath string) {
+	if _, err := os.Stat(inPath); errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%s not found", inPath)
+	}
+	return filepath.Join(t.TempDir(), "out.sav")
+}
+
+func TestCompareData(t *testing.T) {
+	outPath := requireSavegame(t)
+	game, err := savegame.Load(inPath, savegame.LoadOptions{})
+	if err != nil {
+		t.Errorf("Could not load from: %s due to error:\n%s", inPath, err)
+	}
+	// The round trip must hold for whatever the savegame holds, issues included.
+	err = savegame.Save(game, outPath, savegame.SaveOptions{SkipValidation: true})
+	if err != nil {
+		t.Errorf("Could not save to: %s due to error:\n%s", outPath, err)
+	}
+	game1, err := savegame.Load(outPath, savegame.LoadOptions{})
+	if err != nil {
+		t.Errorf("Could not load from: %s due to error:\n%s", outPath, err)
+	}
+	// Compare as JSON, which unlike reflect.DeepEqual treats equal NaNs as equal.
+	json0, err := json.Marshal(game)
+	if err != nil {
+		t.Errorf("Could not marshal %s due to error:\n%s", inPath, err)
+	}
+	json1, err := json.Marshal(game1)
+	if err != nil {
+		t.Errorf("Could not marshal %s due to error:\n%s", outPath, err)
+	}
+	if !bytes.Equal(json0, json1) {
+		t.Errorf("%s's data was different after saving and reloading", inPath)
+	}
+}
+
+func TestCompareSaveFiles(t *testing.T) {
+	outPath := requireSavegame(t)
+	game, err := savegame.Load(inPath, savegame.LoadOptions{})
+	if err != nil {
+		t.Errorf("Could not load from: %s due to error:\n%s", inPath, err)
+	}
+	// The round trip must hold for whatever the savegame holds, issues included.
+	err = savegame.Save(game, outPath, savegame.SaveOptions{SkipValidation: true})
+	if err != nil {
+		t.Errorf("Could not save to: %s due to error:\n%s", outPath, err)
+	}
+	h1, err := hashFile(inPath)
+	if err != nil {
+		t.Errorf("Could not hash %s due to error:\n%s", outPath, err)
+	}
+	h2, err := hashFile(outPath)
+	if err != nil {
+		t.Errorf("Could not hash %s due to error:\n%s", outPath, err)
+	}
+	if !bytes.Equal(h1, h2) {
+		t.Errorf("%s's hash was different after loading and saving", inPath)
+	}
+}
+
+func TestImportNamedExport(t *testing.T) {
+	game := savegame.Game{Troops: make([]savegame.Troop, 2)}
+	if err := game.Troops[1].SetSkill(savegame.LoadOptions{}, 0, 3); err != nil {
+		t.Fatal(err)
+	}
+	plainPath := filepath.Join(t.TempDir(), "plain.json")
+	if err := ExportToJson(game, plainPath, ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportFromJson(plainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Troops[1].Skill(0) != 3 {
+		t.Errorf("expected troop 1 to keep skill level 3, got %d", imported.Troops[1].Skill(0))
+	}
+	namedPath := filepath.Join(t.TempDir(), "named.json")
+	if err := ExportToJson(game, namedPath, ExportOptions{NamedGlobalVariables: true, TroopSkills: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportFromJson(namedPath); err == nil {
+		t.Error("expected an error importing a named export")
+	}
+}
+
+func hashFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
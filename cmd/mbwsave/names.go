@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+// The functions below look names up in the module's parties.txt and
+// factions.txt, or in the Native tables of constants.go without them.
+
+// getPartyIds returns the ids of the parties whose string ids start with
+// prefix, e.g. "p_town_".
+func getPartyIds(m *module.Module, prefix string, nativeIds []int) []int {
+	if m == nil || len(m.Parties) == 0 {
+		return nativeIds
+	}
+	var partyIds []int
+	for _, party := range m.Parties {
+		if strings.HasPrefix(party.StringId, prefix) {
+			partyIds = append(partyIds, party.Id)
+		}
+	}
+	return partyIds
+}
+
+// getFactionId resolves a faction given by id or by string id, e.g.
+// "fac_kingdom_1".
+func getFactionId(m *module.Module, s string) (factionId int, ok bool) {
+	if factionId, err := strconv.Atoi(s); err == nil {
+		return factionId, true
+	}
+	if m != nil && len(m.Factions) > 0 {
+		return m.FactionId(s)
+	}
+	factionId, ok = NativeFactionStringIds[s]
+	return factionId, ok
+}
+
+func getKingdomIds(m *module.Module) []int {
+	if m == nil || len(m.Factions) == 0 {
+		return KingdomIds
+	}
+	var kingdomIds []int
+	for _, faction := range m.Factions {
+		if strings.HasPrefix(faction.StringId, "fac_kingdom_") {
+			kingdomIds = append(kingdomIds, faction.Id)
+		}
+	}
+	return kingdomIds
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+	. "github.com/analyticdan/mbw-savegame-editor/savegame"
+)
+
+type report struct {
+	description string
+	run         func(game Game, m *module.Module, args []string) error
+}
+
+var reports = map[string]report{
+	"negative-reputation-fiefs": {
+		description: "fiefs with a negative reputation",
+		run: func(game Game, m *module.Module, args []string) error {
+			printNegativeReputationFiefs(game, m)
+			return nil
+		},
+	},
+	"companion-locations": {
+		description: "the towns companions are waiting in",
+		run: func(game Game, m *module.Module, args []string) error {
+			printCompanionLocations(game, m)
+			return nil
+		},
+	},
+	"companion-polearm": {
+		description: "companions sorted by polearm proficiency",
+		run: func(game Game, m *module.Module, args []string) error {
+			printCompanionsByPolearmProficiency(game, m)
+			return nil
+		},
+	},
+	"bandit-villages": {
+		description: "villages infested by bandits",
+		run: func(game Game, m *module.Module, args []string) error {
+			printVillagesInfestedByBandits(game, m)
+			return nil
+		},
+	},
+	"book-sellers": {
+		description: "the towns the book merchants are in",
+		run: func(game Game, m *module.Module, args []string) error {
+			printTownsWithBookSeller(game, m)
+			return nil
+		},
+	},
+	"no-enterprise": {
+		description: "towns without a player enterprise",
+		run: func(game Game, m *module.Module, args []string) error {
+			printTownsWithoutEnterprise(game, m)
+			return nil
+		},
+	},
+	"garrisons": {
+		description: "fortifications by garrison size for the given factions, by id or string id (default: all kingdoms)",
+		run: func(game Game, m *module.Module, args []string) error {
+			factionIds := getKingdomIds(m)
+			if len(args) > 0 {
+				factionIds = nil
+				for _, arg := range args {
+					factionId, ok := getFactionId(m, arg)
+					if !ok || factionId < 0 || factionId >= len(game.Factions) {
+						return fmt.Errorf("invalid faction %q", arg)
+					}
+					factionIds = append(factionIds, factionId)
+				}
+			}
+			for _, factionId := range factionIds {
+				printFortificationsByGarrisonSize(game, m, factionId)
+			}
+			return nil
+		},
+	},
+}
+
+func printNegativeReputationFiefs(game Game, m *module.Module) {
+	fmt.Println("Negative reputation fiefs: ")
+	for _, fief := range getAllFiefs(game, m) {
+		if reputation := getFiefReputation(m, fief); reputation < 0 {
+			if isVillage(m, fief) {
+				fortification := getVillageFortification(game, m, fief)
+				market := getVillageMarket(game, m, fief)
+				fmt.Printf("%s (fortification: %s, market: %s), reputation: %d\n", fief.Name, &fortification.Name, market.Name, reputation)
+			} else {
+				fmt.Printf("%s; reputation: %d\n", fief.Name, reputation)
+			}
+		}
+	}
+	fmt.Println("---")
+}
+
+func printCompanionLocations(game Game, m *module.Module) {
+	fmt.Println("Companion locations (does not include imprisoned companions or companions on a mission):")
+	for _, companionId := range getCompanionIds(m) {
+		companion := getTroop(game, companionId)
+		if locationId := getTroopLocationId(m, companion); locationId != -1 {
+			location := getFief(game, locationId)
+			fmt.Printf("%s: %s\n", getTroopName(game, m, companionId), location.Name)
+		}
+	}
+	fmt.Println("---")
+}
+
+func printCompanionsByPolearmProficiency(game Game, m *module.Module) {
+	fmt.Println("Companions by proficiencies:")
+	companionIds := getCompanionIds(m)
+	getPolearmProficiencies := func(troop Troop) Float {
+		return troop.Proficiencies[2]
+	}
+	slices.SortFunc(companionIds, func(a, b int) int {
+		aValue := getPolearmProficiencies(getTroop(game, a))
+		bValue := getPolearmProficiencies(getTroop(game, b))
+		return cmp.Compare(aValue, bValue)
+	})
+	for _, companionId := range companionIds {
+		companion := getTroop(game, companionId)
+		fmt.Printf("%s: %.0f\n", getTroopName(game, m, companionId), getPolearmProficiencies(companion))
+	}
+	fmt.Println("---")
+}
+
+func printVillagesInfestedByBandits(game Game, m *module.Module) {
+	fmt.Println("Villages infested by bandits:")
+	var status string
+	for _, village := range getVillages(game, m) {
+		if isVillageInfestedByBandits(m, village) {
+			fortification := getVillageFortification(game, m, village)
+			market := getVillageMarket(game, m, village)
+			if state := getVillageState(m, village); state == VillageBeingRaided {
+				status = " (Being Raided) "
+			} else if state == VillageLooted {
+				status = " (Looted) "
+			}
+			fmt.Printf("%s%s (fortification: %s, market: %s)\n", village.Name, status, fortification.Name, market.Name)
+		}
+	}
+	fmt.Println("---")
+}
+
+func printFortificationsByGarrisonSize(game Game, m *module.Module, factionId int) {
+	fmt.Printf("%s's Fortifications by Garrison Size:\n", getFaction(game, factionId).Name)
+	fortifications := getFortifications(game, m)
+	slices.SortFunc(fortifications, func(a, b Party) int {
+		return cmp.Compare(getGarrisonSize(a), getGarrisonSize(b))
+	})
+	for _, fortification := range fortifications {
+
+		if factionId == int(fortification.FactionId) {
+			var ladderString, yoursString string
+			if isFortificationSiegedWithLadders(m, fortification) {
+				ladderString = " (ladder)"
+			}
+			if getFiefLordId(m, fortification) == 0 {
+				yoursString = " (yours) "
+			}
+			fmt.Printf("%s%s%s: %d troops\n", fortification.Name, ladderString, yoursString, getGarrisonSize(fortification))
+		}
+	}
+	fmt.Println("---")
+}
+
+func printTownsWithBookSeller(game Game, m *module.Module) {
+	var bookSeller1Town, bookSeller2Town Party
+	for _, town := range getTowns(game, m) {
+		townBookSeller := getTownBookSeller(m, town)
+		switch townBookSeller {
+		case BookSeller1:
+			bookSeller1Town = town
+		case BookSeller2:
+			bookSeller2Town = town
+		}
+	}
+	fmt.Printf("Book Merchant 1 at: %s, Book Merchant 2 at: %s\n", &bookSeller1Town.Name, &bookSeller2Town.Name)
+	fmt.Println("---")
+}
+
+func printTownsWithoutEnterprise(game Game, m *module.Module) {
+	fmt.Println("Towns without enterprises:")
+	for _, town := range getTowns(game, m) {
+		if !hasTownEnterprise(m, town) {
+			fmt.Println(town.Name)
+		}
+	}
+	fmt.Println("---")
+}
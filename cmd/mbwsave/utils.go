@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+	. "github.com/analyticdan/mbw-savegame-editor/savegame"
+)
+
+func getFief(game Game, fiefId int) Party {
+	return game.PartyRecords[fiefId].Party
+}
+
+func getFiefs(game Game, fiefIds []int) []Party {
+	fiefs := make([]Party, len(fiefIds))
+	for i, fiefId := range fiefIds {
+		fiefs[i] = getFief(game, fiefId)
+	}
+	return fiefs
+}
+
+func getTowns(game Game, m *module.Module) []Party {
+	return getFiefs(game, getPartyIds(m, "p_town_", TownIds))
+}
+
+func getCastles(game Game, m *module.Module) []Party {
+	return getFiefs(game, getPartyIds(m, "p_castle_", CastleIds))
+}
+
+func getVillages(game Game, m *module.Module) []Party {
+	return getFiefs(game, getPartyIds(m, "p_village_", VillageIds))
+}
+
+func getFortifications(game Game, m *module.Module) []Party {
+	return slices.Concat(getTowns(game, m), getCastles(game, m))
+}
+
+func getAllFiefs(game Game, m *module.Module) []Party {
+	return slices.Concat(getTowns(game, m), getCastles(game, m), getVillages(game, m))
+}
+
+type slotted interface {
+	Slot(options LoadOptions, name string) (Int64, error)
+}
+
+// getSlot reads a slot named in module.NativeSlots, which LoadOptions.SlotIndex
+// falls back to for the slots a module does not name, so it cannot fail.
+func getSlot(m *module.Module, object slotted, name string) Int64 {
+	value, err := object.Slot(LoadOptions{Module: m}, name)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func getFiefLordId(m *module.Module, fief Party) int {
+	return int(getSlot(m, fief, "slot_town_lord"))
+}
+
+func getFiefReputation(m *module.Module, fief Party) int {
+	return int(getSlot(m, fief, "slot_center_player_relation"))
+}
+
+func getFiefOriginalFactionId(m *module.Module, fief Party) int {
+	return int(getSlot(m, fief, "slot_center_original_faction"))
+}
+
+func isVillage(m *module.Module, party Party) bool {
+	return getSlot(m, party, "slot_party_type") == PartyTypeVillage
+}
+
+func getVillageState(m *module.Module, village Party) int {
+	return int(getSlot(m, village, "slot_village_state"))
+}
+
+func isVillageInfestedByBandits(m *module.Module, village Party) bool {
+	return getSlot(m, village, "slot_village_infested_by_bandits") != 0
+}
+
+func getVillageFortification(game Game, m *module.Module, village Party) Party {
+	return getFief(game, int(getSlot(m, village, "slot_village_bound_center")))
+}
+
+func getVillageMarket(game Game, m *module.Module, village Party) Party {
+	return getFief(game, int(getSlot(m, village, "slot_village_market_town")))
+}
+
+func getTownBookSeller(m *module.Module, town Party) int {
+	return int(getSlot(m, town, "slot_center_tavern_bookseller"))
+}
+
+func hasTownEnterprise(m *module.Module, town Party) bool {
+	return getSlot(m, town, "slot_center_player_enterprise") != 0
+}
+
+func isFortificationSiegedWithLadders(m *module.Module, fortification Party) bool {
+	return getSlot(m, fortification, "slot_center_siege_with_belfry") == 0
+}
+
+func getGarrisonSize(party Party) int {
+	size := 0
+	for _, stack := range party.Stacks {
+		if stack.Flags == 0 {
+			size += int(stack.NumTroops)
+		}
+	}
+	return size
+}
+
+func getTroop(game Game, troopId int) Troop {
+	return game.Troops[troopId]
+}
+
+func getTroopRenown(m *module.Module, troop Troop) int {
+	return int(getSlot(m, troop, "slot_troop_renown"))
+}
+
+func getTroopLocationId(m *module.Module, troop Troop) int {
+	return int(getSlot(m, troop, "slot_troop_cur_center"))
+}
+
+func getTroopName(game Game, m *module.Module, troopId int) string {
+	if troop := getTroop(game, troopId); troop.Renamed {
+		return troop.Name.String()
+	}
+	if m != nil {
+		if troop, ok := m.Troop(troopId); ok {
+			return troop.Name
+		}
+	}
+	if name, ok := CompanionsNameMap[troopId]; ok {
+		return name
+	}
+	return fmt.Sprintf("troop %d", troopId)
+}
+
+// getCompanionIds returns the ids of the module's trp_npc.* heroes, which is
+// how Native and the mods based on it name companions.
+func getCompanionIds(m *module.Module) []int {
+	if m == nil || len(m.Troops) == 0 {
+		return slices.Clone(CompanionIds)
+	}
+	var companionIds []int
+	for _, troop := range m.Troops {
+		if troop.IsHero() && strings.HasPrefix(troop.StringId, "trp_npc") {
+			companionIds = append(companionIds, troop.Id)
+		}
+	}
+	return companionIds
+}
+
+func getFaction(game Game, factionId int) Faction {
+	return game.Factions[factionId]
+}
+
+//Lord reputation = Troop.Slots[52]
+
+func unequipCompanionItems(game Game, equipmentSlot int, inventOffset int) {
+	// Use order of proficiencies above to ensure the best characters get the first items.
+	heroIds := []int{197, 199, 203, 202, 207, 201, 198, 200, 206, 208, 209, 204, 194, 195, 205, 196}
+
+	for i, heroId := range heroIds {
+		game.Troops[0].InventoryItems[inventOffset+i] = game.Troops[heroId].EquippedItems[equipmentSlot]
+		game.Troops[heroId].EquippedItems[equipmentSlot].ItemKindId = -1
+	}
+}
+
+func equipCompanionItems(game Game, equipmentSlot int, inventOffset int) {
+	// Use order of proficiencies above to ensure the best characters get the first items.
+	heroIds := []int{197, 199, 203, 202, 207, 201, 198, 200, 206, 208, 209, 204, 194, 195, 205, 196}
+
+	for i, heroId := range heroIds {
+		game.Troops[heroId].EquippedItems[equipmentSlot] = game.Troops[0].InventoryItems[inventOffset+i]
+		game.Troops[0].InventoryItems[inventOffset+i].ItemKindId = -1
+	}
+}
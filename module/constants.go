@@ -0,0 +1,95 @@
+package module
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Constants holds the integer constants of a module system's
+// module_constants.py, such as slot_town_lord = 7.
+type Constants map[string]int
+
+// ParseConstants reads the "name = value" assignments of module_constants.py.
+// Values may be integers or sums and differences of integers and constants
+// assigned earlier in the file; assignments of anything else are skipped.
+func ParseConstants(r io.Reader) (Constants, error) {
+	constants := make(Constants)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		name, expression, found := strings.Cut(line, "=")
+		if !found || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if !isIdentifier(name) {
+			continue
+		}
+		if value, ok := constants.evaluate(expression); ok {
+			constants[name] = value
+		}
+	}
+	return constants, scanner.Err()
+}
+
+func ReadConstants(path string) (Constants, error) {
+	return readFile(path, ParseConstants)
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c != '_' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !(i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate computes expressions such as "slot_town_lord + 1", the only kind
+// module_constants.py uses to derive one constant from another.
+func (constants Constants) evaluate(expression string) (value int, ok bool) {
+	if strings.ContainsAny(expression, "()[]{},*/\"'") {
+		return 0, false
+	}
+	expression = strings.NewReplacer("+", " + ", "-", " - ").Replace(expression)
+	sign := 1
+	expectOperand := true
+	for _, token := range strings.Fields(expression) {
+		if !expectOperand {
+			switch token {
+			case "+":
+				sign = 1
+			case "-":
+				sign = -1
+			default:
+				return 0, false
+			}
+			expectOperand = true
+			continue
+		}
+		if token == "-" {
+			sign = -sign
+			continue
+		}
+		operand, err := strconv.ParseInt(token, 0, 64)
+		if err != nil {
+			known, found := constants[token]
+			if !found {
+				return 0, false
+			}
+			operand = int64(known)
+		}
+		value += sign * int(operand)
+		expectOperand = false
+	}
+	return value, !expectOperand
+}
@@ -0,0 +1,67 @@
+package module
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConstants(t *testing.T) {
+	text := `from header_common import *
+########################################################
+##  PARTY SLOTS
+########################################################
+slot_party_type                = 0  #spt_caravan, spt_town, spt_castle
+slot_town_lord                 = 7
+slot_party_template_lord       = 0x10
+slot_town_trade_routes_begin   = slot_town_lord + 3 - 1
+slot_troop_renown              = -(1)
+slot_faction_culture           = 2 * 3
+slot_lord_reputation_type      = 15
+num_trade_goods                = itm_siege_supply - itm_spice
+  indented_name = 5
+spt_village = 4
+`
+	constants, err := ParseConstants(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Constants{
+		"slot_party_type":              0,
+		"slot_town_lord":               7,
+		"slot_party_template_lord":     16,
+		"slot_town_trade_routes_begin": 9,
+		"slot_lord_reputation_type":    15,
+		"spt_village":                  4,
+	}
+	if len(constants) != len(expected) {
+		t.Errorf("expected %d constants, got %v", len(expected), constants)
+	}
+	for name, value := range expected {
+		if constants[name] != value {
+			t.Errorf("expected %s = %d, got %d", name, value, constants[name])
+		}
+	}
+
+	slots := SlotsFromConstants(constants)
+	checks := []struct {
+		kind  SlotKind
+		name  string
+		index int
+	}{
+		{PartySlot, "slot_town_lord", 7},
+		{PartySlot, "slot_town_trade_routes_begin", 9},
+		{PartyTemplateSlot, "slot_party_template_lord", 16},
+		{TroopSlot, "slot_lord_reputation_type", 15},
+	}
+	for _, check := range checks {
+		if index, ok := slots.Index(check.kind, check.name); !ok || index != check.index {
+			t.Errorf("expected %s at %d, got %d (found: %t)", check.name, check.index, index, ok)
+		}
+	}
+	if _, ok := slots.Index(PartySlot, "slot_party_template_lord"); ok {
+		t.Error("expected slot_party_template_lord not to be a party slot")
+	}
+	if _, ok := slots.Index(PartySlot, "spt_village"); ok {
+		t.Error("expected spt_village not to be a slot")
+	}
+}
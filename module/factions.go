@@ -0,0 +1,60 @@
+package module
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Faction struct {
+	Id        int
+	StringId  string
+	Name      string
+	Flags     uint64
+	Color     uint32
+	Relations []float64
+	Ranks     []string
+}
+
+func ParseFactions(r io.Reader) ([]Faction, error) {
+	t := newTokenizer(r)
+	if version := t.header("factions"); t.err == nil && version != 1 {
+		return nil, fmt.Errorf("unsupported factions file version %d", version)
+	}
+	n := t.count()
+	factions := make([]Faction, 0, min(n, 4096))
+	for id := 0; t.err == nil && id < n; id++ {
+		faction := Faction{Id: id}
+		// Every faction starts with a 0 that the module system writes without
+		// a space before the string id, as in "0fac_commoners".
+		faction.StringId = t.next()
+		if faction.StringId == "0" {
+			faction.StringId = t.next()
+		} else {
+			faction.StringId = strings.TrimPrefix(faction.StringId, "0")
+		}
+		faction.Name = displayName(t.next())
+		faction.Flags = t.uint64()
+		faction.Color = uint32(t.uint64())
+		faction.Relations = make([]float64, 0, min(n, 4096))
+		for range n {
+			faction.Relations = append(faction.Relations, t.float())
+		}
+		numRanks := t.count()
+		for i := 0; t.err == nil && i < numRanks; i++ {
+			faction.Ranks = append(faction.Ranks, displayName(t.next()))
+		}
+		if t.err != nil {
+			return nil, fmt.Errorf("faction %d (%s): %w", id, faction.StringId, t.err)
+		}
+		factions = append(factions, faction)
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return factions, nil
+}
+
+func ReadFactions(path string) ([]Faction, error) {
+	return readFile(path, ParseFactions)
+}
@@ -0,0 +1,56 @@
+package module
+
+import (
+	"strings"
+	"testing"
+)
+
+const testFactions = `factionsfile version 1
+3
+0fac_no_faction No_Faction 0 16777215 
+ 0.000000  0.000000  0.000000 
+0 
+0fac_commoners Commoners 0 10066329 
+ 0.000000  0.100000  -0.050000 
+0 
+0 fac_kingdom_1 Kingdom_of_Swadia 0 14540253 
+ 0.000000  -0.050000  0.500000 
+2 Knight Lord 
+`
+
+func TestParseFactions(t *testing.T) {
+	factions, err := ParseFactions(strings.NewReader(testFactions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(factions) != 3 {
+		t.Fatalf("expected 3 factions, got %d", len(factions))
+	}
+	commoners, swadia := factions[1], factions[2]
+	if commoners.StringId != "fac_commoners" || commoners.Color != 10066329 {
+		t.Errorf("unexpected commoners faction %+v", commoners)
+	}
+	if commoners.Relations[1] != 0.1 || commoners.Relations[2] != -0.05 {
+		t.Errorf("unexpected commoners relations %v", commoners.Relations)
+	}
+	if swadia.Id != 2 || swadia.StringId != "fac_kingdom_1" || swadia.Name != "Kingdom of Swadia" {
+		t.Errorf("unexpected Swadia faction %+v", swadia)
+	}
+	if len(swadia.Ranks) != 2 || swadia.Ranks[1] != "Lord" {
+		t.Errorf("expected Swadia's ranks to be [Knight Lord], got %v", swadia.Ranks)
+	}
+
+	m := &Module{Factions: factions}
+	if factionId, ok := m.FactionId("fac_kingdom_1"); !ok || factionId != 2 {
+		t.Errorf("expected fac_kingdom_1 to be faction 2, got %d (found: %t)", factionId, ok)
+	}
+	if _, ok := m.FactionId("fac_kingdom_7"); ok {
+		t.Error("expected fac_kingdom_7 not to be found")
+	}
+}
+
+func TestParseFactionsTruncated(t *testing.T) {
+	if _, err := ParseFactions(strings.NewReader(testFactions[:200])); err == nil {
+		t.Error("expected an error for a truncated factions file")
+	}
+}
@@ -0,0 +1,95 @@
+package module
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Ini holds the key = value settings of a module.ini. Keys such as
+// load_mod_resource may appear several times, so every value is kept in the
+// order it was read.
+type Ini map[string][]string
+
+func ParseIni(r io.Reader) (Ini, error) {
+	ini := make(Ini)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		ini[key] = append(ini[key], strings.TrimSpace(value))
+	}
+	return ini, scanner.Err()
+}
+
+func ReadIni(path string) (Ini, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseIni(file)
+}
+
+// Value returns the last value set for key, as the game lets later lines
+// override earlier ones.
+func (ini Ini) Value(key string) (string, bool) {
+	values := ini[key]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+func (ini Ini) Values(key string) []string {
+	return ini[key]
+}
+
+// Bool returns the value of key, or fallback if it is unset or not a number
+// or boolean. The game treats any non-zero number as true.
+func (ini Ini) Bool(key string, fallback bool) bool {
+	value, ok := ini.Value(key)
+	if !ok {
+		return fallback
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f != 0
+	}
+	return fallback
+}
+
+func (ini Ini) Int(key string, fallback int) int {
+	value, ok := ini.Value(key)
+	if !ok {
+		return fallback
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return i
+}
+
+func (ini Ini) Float(key string, fallback float64) float64 {
+	value, ok := ini.Value(key)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
@@ -0,0 +1,47 @@
+package module
+
+import (
+	"strings"
+	"testing"
+)
+
+const testIni = `# A module.ini excerpt
+load_mod_resource = pictures
+load_mod_resource = textures # trailing comment
+dont_load_regular_troop_inventories = 1
+time_multiplier = 0.25
+battle_size_max = 150
+`
+
+func TestParseIni(t *testing.T) {
+	ini, err := ParseIni(strings.NewReader(testIni))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values := ini.Values("load_mod_resource"); len(values) != 2 || values[1] != "textures" {
+		t.Errorf("expected load_mod_resource to be [pictures textures], got %v", values)
+	}
+	if !ini.Bool("dont_load_regular_troop_inventories", false) {
+		t.Error("expected dont_load_regular_troop_inventories to be true")
+	}
+	if f := ini.Float("time_multiplier", 1); f != 0.25 {
+		t.Errorf("expected time_multiplier 0.25, got %v", f)
+	}
+	if i := ini.Int("battle_size_max", 0); i != 150 {
+		t.Errorf("expected battle_size_max 150, got %d", i)
+	}
+	if i := ini.Int("missing", -1); i != -1 {
+		t.Errorf("expected fallback -1, got %d", i)
+	}
+}
+
+func TestRegularTroopInventory(t *testing.T) {
+	m := &Module{Ini: Ini{"dont_load_regular_troop_inventories": {"1"}}}
+	if m.RegularTroopInventory() {
+		t.Error("expected regular troop inventories not to be loaded")
+	}
+	m.Ini = Ini{}
+	if !m.RegularTroopInventory() {
+		t.Error("expected regular troop inventories to be loaded by default")
+	}
+}
@@ -0,0 +1,205 @@
+package module
+
+import (
+	"fmt"
+	"io"
+)
+
+// ItemType is the itp_type_.* part of an item kind's flags.
+type ItemType int
+
+const (
+	// See itp_type_.* in header_items.py
+	ItemTypeHorse ItemType = iota + 1
+	ItemTypeOneHandedWeapon
+	ItemTypeTwoHandedWeapon
+	ItemTypePolearm
+	ItemTypeArrows
+	ItemTypeBolts
+	ItemTypeShield
+	ItemTypeBow
+	ItemTypeCrossbow
+	ItemTypeThrown
+	ItemTypeGoods
+	ItemTypeHeadArmor
+	ItemTypeBodyArmor
+	ItemTypeFootArmor
+	ItemTypeHandArmor
+	ItemTypePistol
+	ItemTypeMusket
+	ItemTypeBullets
+	ItemTypeAnimal
+	ItemTypeBook
+)
+
+var itemTypeNames = []string{
+	ItemTypeHorse:           "horse",
+	ItemTypeOneHandedWeapon: "one handed weapon",
+	ItemTypeTwoHandedWeapon: "two handed weapon",
+	ItemTypePolearm:         "polearm",
+	ItemTypeArrows:          "arrows",
+	ItemTypeBolts:           "bolts",
+	ItemTypeShield:          "shield",
+	ItemTypeBow:             "bow",
+	ItemTypeCrossbow:        "crossbow",
+	ItemTypeThrown:          "thrown",
+	ItemTypeGoods:           "goods",
+	ItemTypeHeadArmor:       "head armor",
+	ItemTypeBodyArmor:       "body armor",
+	ItemTypeFootArmor:       "foot armor",
+	ItemTypeHandArmor:       "hand armor",
+	ItemTypePistol:          "pistol",
+	ItemTypeMusket:          "musket",
+	ItemTypeBullets:         "bullets",
+	ItemTypeAnimal:          "animal",
+	ItemTypeBook:            "book",
+}
+
+func (itemType ItemType) String() string {
+	if itemType > 0 && int(itemType) < len(itemTypeNames) {
+		return itemTypeNames[itemType]
+	}
+	return fmt.Sprintf("item type %d", int(itemType))
+}
+
+type DamageType int
+
+const (
+	// See cut, pierce and blunt in header_items.py
+	Cut DamageType = iota
+	Pierce
+	Blunt
+)
+
+func (damageType DamageType) String() string {
+	switch damageType {
+	case Cut:
+		return "cut"
+	case Pierce:
+		return "pierce"
+	case Blunt:
+		return "blunt"
+	}
+	return fmt.Sprintf("damage type %d", int(damageType))
+}
+
+type Damage struct {
+	Amount int
+	Type   DamageType
+}
+
+/* Damage is written as its amount with the damage type in bits 8 and 9. */
+func parseDamage(value int) Damage {
+	return Damage{Amount: value & 0xff, Type: DamageType(value>>8) & 0x3}
+}
+
+func (damage Damage) String() string {
+	return fmt.Sprintf("%d %s", damage.Amount, damage.Type)
+}
+
+type ItemMesh struct {
+	Name         string
+	ModifierBits uint64
+}
+
+// ItemKind is an entry of item_kinds1.txt. Its Id is the ItemKindId of the
+// items in a savegame.
+type ItemKind struct {
+	Id           int
+	StringId     string
+	Name         string
+	PluralName   string
+	Meshes       []ItemMesh
+	Flags        uint64
+	Capabilities uint64
+	Price        int
+	// ModifierBits has bit 1 << imod set for every modifier the item can have.
+	ModifierBits uint64
+	Weight       float64
+	Abundance    int
+	HeadArmor    int
+	BodyArmor    int
+	LegArmor     int
+	Difficulty   int
+	HitPoints    int
+	Speed        int
+	MissileSpeed int
+	WeaponLength int
+	MaxAmmo      int
+	ThrustDamage Damage
+	SwingDamage  Damage
+	FactionIds   []int
+}
+
+func (kind ItemKind) Type() ItemType {
+	return ItemType(kind.Flags & 0xff)
+}
+
+/* Triggers are compiled operations; only their length is needed to skip them. */
+func skipTriggers(t *tokenizer) {
+	numTriggers := t.count()
+	for i := 0; t.err == nil && i < numTriggers; i++ {
+		t.float()
+		numOperations := t.count()
+		for j := 0; t.err == nil && j < numOperations; j++ {
+			t.next()
+			numParameters := t.count()
+			for k := 0; t.err == nil && k < numParameters; k++ {
+				t.next()
+			}
+		}
+	}
+}
+
+func ParseItemKinds(r io.Reader) ([]ItemKind, error) {
+	t := newTokenizer(r)
+	if version := t.header("items"); t.err == nil && version != 3 {
+		return nil, fmt.Errorf("unsupported items file version %d", version)
+	}
+	n := t.count()
+	kinds := make([]ItemKind, 0, min(n, 4096))
+	for id := 0; t.err == nil && id < n; id++ {
+		kind := ItemKind{Id: id}
+		kind.StringId = t.next()
+		kind.Name = displayName(t.next())
+		kind.PluralName = displayName(t.next())
+		numMeshes := t.count()
+		for i := 0; t.err == nil && i < numMeshes; i++ {
+			kind.Meshes = append(kind.Meshes, ItemMesh{Name: t.next(), ModifierBits: t.uint64()})
+		}
+		kind.Flags = t.uint64()
+		kind.Capabilities = t.uint64()
+		kind.Price = t.int()
+		kind.ModifierBits = t.uint64()
+		kind.Weight = t.float()
+		kind.Abundance = t.int()
+		kind.HeadArmor = t.int()
+		kind.BodyArmor = t.int()
+		kind.LegArmor = t.int()
+		kind.Difficulty = t.int()
+		kind.HitPoints = t.int()
+		kind.Speed = t.int()
+		kind.MissileSpeed = t.int()
+		kind.WeaponLength = t.int()
+		kind.MaxAmmo = t.int()
+		kind.ThrustDamage = parseDamage(t.int())
+		kind.SwingDamage = parseDamage(t.int())
+		numFactions := t.count()
+		for i := 0; t.err == nil && i < numFactions; i++ {
+			kind.FactionIds = append(kind.FactionIds, t.int())
+		}
+		skipTriggers(t)
+		if t.err != nil {
+			return nil, fmt.Errorf("item kind %d (%s): %w", id, kind.StringId, t.err)
+		}
+		kinds = append(kinds, kind)
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return kinds, nil
+}
+
+func ReadItemKinds(path string) ([]ItemKind, error) {
+	return readFile(path, ParseItemKinds)
+}
@@ -0,0 +1,73 @@
+package module
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testItemKinds follows the layout written by process_items.py: the stats of
+// an item, then its factions, then its simple triggers.
+const testItemKinds = `itemsfile version 3
+3
+ itm_no_item INVALID_ITEM INVALID_ITEM 1 practice_sword 0  0 0 3 0 0.000000 0 0 0 0 0 0 0 0 0 0 0 0
+  0
+0
+ itm_heavy_lance Heavy_Lance Heavy_Lance 1 spear_a_3m 0  4 8658944 340 28672 2.300000 100 0 0 0 13 0 78 0 250 0 282 0
+  2
+  3  4
+0
+ itm_torch Torch Torch 1 club 0  2 0 11 1 2.500000 100 0 0 0 0 0 95 0 95 0 0 267
+  0
+1
+-50.000000  5 1790 3 0 60 0 1802 1 936748722493063171 1802 1 936748722493063172 1950 3 150 130 70 1960 2 10 30 
+
+`
+
+func TestParseItemKinds(t *testing.T) {
+	kinds, err := ParseItemKinds(strings.NewReader(testItemKinds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kinds) != 3 {
+		t.Fatalf("expected 3 item kinds, got %d", len(kinds))
+	}
+	lance := kinds[1]
+	if lance.Id != 1 || lance.StringId != "itm_heavy_lance" || lance.Name != "Heavy Lance" {
+		t.Errorf("unexpected lance identity: %d %s %q", lance.Id, lance.StringId, lance.Name)
+	}
+	if lance.Type() != ItemTypePolearm {
+		t.Errorf("expected a polearm, got %s", lance.Type())
+	}
+	if lance.Price != 340 || lance.Weight != 2.3 || lance.Difficulty != 13 || lance.Speed != 78 || lance.WeaponLength != 250 {
+		t.Errorf("unexpected lance stats: %+v", lance)
+	}
+	if lance.ThrustDamage != (Damage{Amount: 26, Type: Pierce}) {
+		t.Errorf("expected 26 pierce thrust damage, got %s", lance.ThrustDamage)
+	}
+	if lance.ModifierBits != 28672 {
+		t.Errorf("unexpected modifier bits %d", lance.ModifierBits)
+	}
+	if len(lance.FactionIds) != 2 || lance.FactionIds[1] != 4 {
+		t.Errorf("expected factions [3 4], got %v", lance.FactionIds)
+	}
+	torch := kinds[2]
+	if torch.StringId != "itm_torch" || torch.Speed != 95 || len(torch.FactionIds) != 0 {
+		t.Errorf("unexpected torch after the lance's factions and its own triggers: %+v", torch)
+	}
+}
+
+func TestItemKindModifiers(t *testing.T) {
+	kind := ItemKind{ModifierBits: 1<<ImodRusty | 1<<ImodBalanced | 1<<ImodMasterwork}
+	modifiers := kind.Modifiers()
+	expected := []ItemModifier{ImodPlain, ImodRusty, ImodBalanced, ImodMasterwork}
+	if !slices.Equal(modifiers, expected) {
+		t.Errorf("expected modifiers %v, got %v", expected, modifiers)
+	}
+	if !kind.HasModifier(ImodBalanced) || kind.HasModifier(ImodLordly) || kind.HasModifier(NumItemModifiers) {
+		t.Error("expected only the modifiers in ModifierBits")
+	}
+	if name := ImodTwoDayOld.String(); name != "Two Days-old" {
+		t.Errorf("expected Two Days-old, got %q", name)
+	}
+}
@@ -0,0 +1,128 @@
+package module
+
+import "fmt"
+
+// ItemModifier is the quality of an item, such as balanced or rusty.
+type ItemModifier int
+
+const (
+	// See imod_.* in header_item_modifiers.py
+	ImodPlain ItemModifier = iota
+	ImodCracked
+	ImodRusty
+	ImodBent
+	ImodChipped
+	ImodBattered
+	ImodPoor
+	ImodCrude
+	ImodOld
+	ImodCheap
+	ImodFine
+	ImodWellMade
+	ImodSharp
+	ImodBalanced
+	ImodTempered
+	ImodDeadly
+	ImodExquisite
+	ImodMasterwork
+	ImodHeavy
+	ImodStrong
+	ImodPowerful
+	ImodTattered
+	ImodRagged
+	ImodRough
+	ImodSturdy
+	ImodThick
+	ImodHardened
+	ImodReinforced
+	ImodSuperb
+	ImodLordly
+	ImodLame
+	ImodSwaybacked
+	ImodStubborn
+	ImodTimid
+	ImodMeek
+	ImodSpirited
+	ImodChampion
+	ImodFresh
+	ImodDayOld
+	ImodTwoDayOld
+	ImodSmelling
+	ImodRotten
+	ImodLargeBag
+	NumItemModifiers
+)
+
+/* As in item_modifiers.py, whose names the game shows before item names. */
+var itemModifierNames = [NumItemModifiers]string{
+	ImodPlain:      "Plain",
+	ImodCracked:    "Cracked",
+	ImodRusty:      "Rusty",
+	ImodBent:       "Bent",
+	ImodChipped:    "Chipped",
+	ImodBattered:   "Battered",
+	ImodPoor:       "Poor",
+	ImodCrude:      "Crude",
+	ImodOld:        "Old",
+	ImodCheap:      "Cheap",
+	ImodFine:       "Fine",
+	ImodWellMade:   "Well Made",
+	ImodSharp:      "Sharp",
+	ImodBalanced:   "Balanced",
+	ImodTempered:   "Tempered",
+	ImodDeadly:     "Deadly",
+	ImodExquisite:  "Exquisite",
+	ImodMasterwork: "Masterwork",
+	ImodHeavy:      "Heavy",
+	ImodStrong:     "Strong",
+	ImodPowerful:   "Powerful",
+	ImodTattered:   "Tattered",
+	ImodRagged:     "Ragged",
+	ImodRough:      "Rough",
+	ImodSturdy:     "Sturdy",
+	ImodThick:      "Thick",
+	ImodHardened:   "Hardened",
+	ImodReinforced: "Reinforced",
+	ImodSuperb:     "Superb",
+	ImodLordly:     "Lordly",
+	ImodLame:       "Lame",
+	ImodSwaybacked: "Swaybacked",
+	ImodStubborn:   "Stubborn",
+	ImodTimid:      "Timid",
+	ImodMeek:       "Meek",
+	ImodSpirited:   "Spirited",
+	ImodChampion:   "Champion",
+	ImodFresh:      "Fresh",
+	ImodDayOld:     "Day-old",
+	ImodTwoDayOld:  "Two Days-old",
+	ImodSmelling:   "Smelling",
+	ImodRotten:     "Rotten",
+	ImodLargeBag:   "Large Bag",
+}
+
+func (modifier ItemModifier) String() string {
+	if modifier >= 0 && modifier < NumItemModifiers {
+		return itemModifierNames[modifier]
+	}
+	return fmt.Sprintf("item modifier %d", int(modifier))
+}
+
+// Modifiers returns the modifiers an item of this kind can have. Plain is
+// always included, as items whose ModifierBits are 0 are plain.
+func (kind ItemKind) Modifiers() []ItemModifier {
+	modifiers := []ItemModifier{ImodPlain}
+	for modifier := ImodPlain + 1; modifier < NumItemModifiers; modifier++ {
+		if kind.ModifierBits&(1<<modifier) != 0 {
+			modifiers = append(modifiers, modifier)
+		}
+	}
+	return modifiers
+}
+
+// HasModifier reports whether an item of this kind can have the modifier.
+func (kind ItemKind) HasModifier(modifier ItemModifier) bool {
+	if modifier == ImodPlain {
+		return true
+	}
+	return modifier > 0 && modifier < NumItemModifiers && kind.ModifierBits&(1<<modifier) != 0
+}
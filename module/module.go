@@ -0,0 +1,160 @@
+// Package module reads the data files of a Mount and Blade Warband module
+// (e.g. Modules/Native) that are needed to interpret its savegames.
+package module
+
+import "path/filepath"
+
+// Module holds the data files of a module. Only module.ini is required; the
+// fields of data files missing from the module directory are left empty.
+//
+// Constants and Slots come from the module system's module_constants.py,
+// which is not part of a compiled module; it is read if it has been copied
+// into the module directory.
+type Module struct {
+	Dir            string
+	Ini            Ini
+	Troops         []Troop
+	ItemKinds      []ItemKind
+	Skills         []Skill
+	Variables      []string
+	Factions       []Faction
+	Parties        []Party
+	PartyTemplates []PartyTemplate
+	Constants      Constants
+	Slots          Slots
+}
+
+// Load reads the module in dir, e.g. ".../Mount&Blade Warband/Modules/Native".
+func Load(dir string) (*Module, error) {
+	m := &Module{Dir: dir}
+	var err error
+	m.Ini, err = ReadIni(filepath.Join(dir, "module.ini"))
+	if err != nil {
+		return nil, err
+	}
+	m.Troops, err = readOptionalFile(filepath.Join(dir, "troops.txt"), ParseTroops)
+	if err != nil {
+		return nil, err
+	}
+	m.ItemKinds, err = readOptionalFile(filepath.Join(dir, "item_kinds1.txt"), ParseItemKinds)
+	if err != nil {
+		return nil, err
+	}
+	m.Skills, err = readOptionalFile(filepath.Join(dir, "skills.txt"), ParseSkills)
+	if err != nil {
+		return nil, err
+	}
+	m.Factions, err = readOptionalFile(filepath.Join(dir, "factions.txt"), ParseFactions)
+	if err != nil {
+		return nil, err
+	}
+	m.Parties, err = readOptionalFile(filepath.Join(dir, "parties.txt"), ParseParties)
+	if err != nil {
+		return nil, err
+	}
+	m.PartyTemplates, err = readOptionalFile(filepath.Join(dir, "party_templates.txt"), ParsePartyTemplates)
+	if err != nil {
+		return nil, err
+	}
+	m.Variables, err = readOptionalFile(filepath.Join(dir, "variables.txt"), ParseVariables)
+	if err != nil {
+		return nil, err
+	}
+	m.Constants, err = readOptionalFile(filepath.Join(dir, "module_constants.py"), ParseConstants)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Constants) > 0 {
+		m.Slots = SlotsFromConstants(m.Constants)
+	}
+	return m, nil
+}
+
+// RegularTroopInventory reports whether the game stores the gold, inventory
+// and face of regular troops as well as heroes, which changes the layout of
+// the troops in a savegame.
+func (m *Module) RegularTroopInventory() bool {
+	return !m.Ini.Bool("dont_load_regular_troop_inventories", false)
+}
+
+func (m *Module) Troop(troopId int) (troop Troop, ok bool) {
+	if troopId < 0 || troopId >= len(m.Troops) {
+		return troop, false
+	}
+	return m.Troops[troopId], true
+}
+
+// ItemKind returns the item kind of a savegame item's ItemKindId. Empty item
+// slots have an ItemKindId of -1.
+func (m *Module) ItemKind(itemKindId int) (kind ItemKind, ok bool) {
+	if itemKindId < 0 || itemKindId >= len(m.ItemKinds) {
+		return kind, false
+	}
+	return m.ItemKinds[itemKindId], true
+}
+
+func (m *Module) Skill(skillId int) (skill Skill, ok bool) {
+	if skillId < 0 || skillId >= len(m.Skills) {
+		return skill, false
+	}
+	return m.Skills[skillId], true
+}
+
+// Faction returns the faction of a savegame's faction id, e.g. a
+// Party.FactionId.
+func (m *Module) Faction(factionId int) (faction Faction, ok bool) {
+	if factionId < 0 || factionId >= len(m.Factions) {
+		return faction, false
+	}
+	return m.Factions[factionId], true
+}
+
+// FactionId returns the id of the faction with the given string id, e.g.
+// "fac_kingdom_1".
+func (m *Module) FactionId(stringId string) (factionId int, ok bool) {
+	for _, faction := range m.Factions {
+		if faction.StringId == stringId {
+			return faction.Id, true
+		}
+	}
+	return 0, false
+}
+
+// Party returns the party of a savegame's party id, i.e. an index of its
+// PartyRecords. Parties the game creates while playing come after those of
+// the module and are not found.
+func (m *Module) Party(partyId int) (party Party, ok bool) {
+	if partyId < 0 || partyId >= len(m.Parties) {
+		return party, false
+	}
+	return m.Parties[partyId], true
+}
+
+// PartyId returns the id of the party with the given string id, e.g.
+// "p_town_1".
+func (m *Module) PartyId(stringId string) (partyId int, ok bool) {
+	for _, party := range m.Parties {
+		if party.StringId == stringId {
+			return party.Id, true
+		}
+	}
+	return 0, false
+}
+
+func (m *Module) PartyTemplate(partyTemplateId int) (partyTemplate PartyTemplate, ok bool) {
+	if partyTemplateId < 0 || partyTemplateId >= len(m.PartyTemplates) {
+		return partyTemplate, false
+	}
+	return m.PartyTemplates[partyTemplateId], true
+}
+
+// PartyTemplateId returns the id of the party template with the given string
+// id, e.g. "pt_looters".
+func (m *Module) PartyTemplateId(stringId string) (partyTemplateId int, ok bool) {
+	for _, partyTemplate := range m.PartyTemplates {
+		if partyTemplate.StringId == stringId {
+			return partyTemplate.Id, true
+		}
+	}
+	return 0, false
+}
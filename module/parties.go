@@ -0,0 +1,107 @@
+package module
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Party is a party placed on the map by the module, such as a town or a
+// village. Its id is its index in a savegame's PartyRecords.
+type Party struct {
+	Id       int
+	StringId string
+	Name     string
+	Flags    uint64
+}
+
+// PartyTemplate is a template the game creates parties from, such as a
+// bandit party.
+type PartyTemplate struct {
+	Id       int
+	StringId string
+	Name     string
+	Flags    uint64
+}
+
+// Parties and party templates span several lines whose layout varies between
+// module system versions. Only the line that identifies each record is read:
+// it is the only one holding a token with the record's string id prefix.
+func parseRecordLines(r io.Reader, kind string, parse func(fields []string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) != 3 || header[0] != kind+"file" || header[1] != "version" {
+		return fmt.Errorf("expected %sfile version header, got %q", kind, scanner.Text())
+	}
+	if header[2] != "1" {
+		return fmt.Errorf("unsupported %s file version %s", kind, header[2])
+	}
+	for line := 2; scanner.Scan(); line++ {
+		if !parse(strings.Fields(scanner.Text())) {
+			return fmt.Errorf("line %d: malformed record %q", line, scanner.Text())
+		}
+	}
+	return scanner.Err()
+}
+
+func ParseParties(r io.Reader) ([]Party, error) {
+	var parties []Party
+	err := parseRecordLines(r, "parties", func(fields []string) bool {
+		// 1 <id> <id> p_<name> <display name> <flags> ...
+		if len(fields) < 4 || !strings.HasPrefix(fields[3], "p_") {
+			return true
+		}
+		if len(fields) < 6 {
+			return false
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return false
+		}
+		flags, err := parseUint64(fields[5])
+		if err != nil {
+			return false
+		}
+		parties = append(parties, Party{Id: id, StringId: fields[3], Name: displayName(fields[4]), Flags: flags})
+		return true
+	})
+	return parties, err
+}
+
+func ReadParties(path string) ([]Party, error) {
+	return readFile(path, ParseParties)
+}
+
+func ParsePartyTemplates(r io.Reader) ([]PartyTemplate, error) {
+	var partyTemplates []PartyTemplate
+	err := parseRecordLines(r, "partytemplates", func(fields []string) bool {
+		// pt_<name> <display name> <flags> ...
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "pt_") {
+			return true
+		}
+		if len(fields) < 3 {
+			return false
+		}
+		flags, err := parseUint64(fields[2])
+		if err != nil {
+			return false
+		}
+		partyTemplate := PartyTemplate{Id: len(partyTemplates), StringId: fields[0], Name: displayName(fields[1]), Flags: flags}
+		partyTemplates = append(partyTemplates, partyTemplate)
+		return true
+	})
+	return partyTemplates, err
+}
+
+func ReadPartyTemplates(path string) ([]PartyTemplate, error) {
+	return readFile(path, ParsePartyTemplates)
+}
@@ -0,0 +1,55 @@
+package module
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseParties(t *testing.T) {
+	text := `partiesfile version 1
+3 3
+1 0 0 p_main_party Main_Party 256 0 0 0 0 0 0 0 0 0 0 0 0 17.000000 52.500000 17.000000 52.500000 17.000000 52.500000 0.000000 1 0 1 0 0 
+0.000000
+1 1 1 p_temp_party temp_party 536872192 0 0 0 0 0 0 0 0 0 0 0 0 0.000000 0.000000 0.000000 0.000000 0.000000 0.000000 0.000000 0 
+0.000000
+1 2 2 p_town_1 Sargoth -9223372036854775516 0 0 5 0 0 0 0 0 0 0 0 0 -17.600000 -36.700000 -17.600000 -36.700000 -17.600000 -36.700000 0.000000 0 
+2.984513
+`
+	parties, err := ParseParties(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parties) != 3 {
+		t.Fatalf("expected 3 parties, got %d", len(parties))
+	}
+	sargoth := parties[2]
+	if sargoth.Id != 2 || sargoth.StringId != "p_town_1" || sargoth.Name != "Sargoth" || sargoth.Flags != 0x8000000000000124 {
+		t.Errorf("unexpected party %+v", sargoth)
+	}
+	m := &Module{Parties: parties}
+	if partyId, ok := m.PartyId("p_town_1"); !ok || partyId != 2 {
+		t.Errorf("expected p_town_1 to be party 2, got %d (found: %t)", partyId, ok)
+	}
+}
+
+func TestParsePartyTemplates(t *testing.T) {
+	text := `partytemplatesfile version 1
+2
+pt_none none 0 0 0 0 -1 -1 -1 -1 -1 -1 
+pt_looters Looters 4 0 2 1 7 3 45 0 -1 -1 -1 -1 -1 
+`
+	partyTemplates, err := ParsePartyTemplates(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partyTemplates) != 2 {
+		t.Fatalf("expected 2 party templates, got %d", len(partyTemplates))
+	}
+	looters := partyTemplates[1]
+	if looters.Id != 1 || looters.StringId != "pt_looters" || looters.Name != "Looters" || looters.Flags != 4 {
+		t.Errorf("unexpected party template %+v", looters)
+	}
+	if _, err := ParsePartyTemplates(strings.NewReader("partiesfile version 1\n")); err == nil {
+		t.Error("expected an error for the header of another file")
+	}
+}
@@ -0,0 +1,43 @@
+package module
+
+import (
+	"fmt"
+	"io"
+)
+
+type Skill struct {
+	Id          int
+	StringId    string
+	Name        string
+	Flags       uint64
+	MaxLevel    int
+	Description string
+}
+
+// ParseSkills reads skills.txt. Unlike most data files it has no header, only
+// the number of skills.
+func ParseSkills(r io.Reader) ([]Skill, error) {
+	t := newTokenizer(r)
+	n := t.count()
+	skills := make([]Skill, 0, min(n, 4096))
+	for id := 0; t.err == nil && id < n; id++ {
+		skill := Skill{Id: id}
+		skill.StringId = t.next()
+		skill.Name = displayName(t.next())
+		skill.Flags = t.uint64()
+		skill.MaxLevel = t.int()
+		skill.Description = displayName(t.next())
+		if t.err != nil {
+			return nil, fmt.Errorf("skill %d (%s): %w", id, skill.StringId, t.err)
+		}
+		skills = append(skills, skill)
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return skills, nil
+}
+
+func ReadSkills(path string) ([]Skill, error) {
+	return readFile(path, ParseSkills)
+}
@@ -0,0 +1,27 @@
+package module
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSkills(t *testing.T) {
+	text := "2\nskl_trade Trade 2 10 Every_level_of_this_skill_reduces_your_trade_penalty_by_5%._(Party_skill)\n" +
+		"skl_reserved_1 Reserved_Skill_1 257 10 Reserved_skill.\n"
+	skills, err := ParseSkills(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skills) != 2 {
+		t.Fatalf("expected 2 skills, got %d", len(skills))
+	}
+	if skills[0].StringId != "skl_trade" || skills[0].Name != "Trade" || skills[0].MaxLevel != 10 {
+		t.Errorf("unexpected skill %+v", skills[0])
+	}
+	if skills[1].Id != 1 || skills[1].Flags != 257 || skills[1].Name != "Reserved Skill 1" {
+		t.Errorf("unexpected skill %+v", skills[1])
+	}
+	if _, err := ParseSkills(strings.NewReader("2\nskl_trade Trade 2")); err == nil {
+		t.Error("expected an error for a truncated skills file")
+	}
+}
@@ -0,0 +1,85 @@
+package module
+
+import "strings"
+
+// SlotKind is the kind of game object a slot belongs to.
+type SlotKind int
+
+const (
+	PartySlot SlotKind = iota
+	PartyTemplateSlot
+	TroopSlot
+	FactionSlot
+	QuestSlot
+	ItemSlot
+	SceneSlot
+)
+
+/* Longer prefixes come first, as slot_party_template_ also starts with slot_party_. */
+var slotPrefixes = []struct {
+	prefix string
+	kind   SlotKind
+}{
+	{"slot_party_template_", PartyTemplateSlot},
+	{"slot_party_", PartySlot},
+	{"slot_town_", PartySlot},
+	{"slot_center_", PartySlot},
+	{"slot_village_", PartySlot},
+	{"slot_castle_", PartySlot},
+	{"slot_troop_", TroopSlot},
+	{"slot_lord_", TroopSlot},
+	{"slot_faction_", FactionSlot},
+	{"slot_quest_", QuestSlot},
+	{"slot_item_", ItemSlot},
+	{"slot_scene_", SceneSlot},
+}
+
+// Slots maps the slot_.* names of each kind of object to slot indices.
+type Slots map[SlotKind]map[string]int
+
+// SlotsFromConstants picks the slot_.* names out of module_constants.py. The
+// module system does not record which kind of object a slot belongs to, so it
+// is told from the name's prefix, e.g. slot_town_lord is a party slot.
+func SlotsFromConstants(constants Constants) Slots {
+	slots := make(Slots)
+	for name, index := range constants {
+		for _, p := range slotPrefixes {
+			if strings.HasPrefix(name, p.prefix) {
+				if slots[p.kind] == nil {
+					slots[p.kind] = make(map[string]int)
+				}
+				slots[p.kind][name] = index
+				break
+			}
+		}
+	}
+	return slots
+}
+
+// Index returns the index of the slot called name of the given kind.
+func (slots Slots) Index(kind SlotKind, name string) (index int, ok bool) {
+	index, ok = slots[kind][name]
+	return index, ok
+}
+
+// NativeSlots holds the slots of Native's module_constants.py that are used by
+// this project, for modules whose module_constants.py is not at hand.
+var NativeSlots = Slots{
+	PartySlot: {
+		"slot_party_type":                  0,
+		"slot_town_lord":                   7,
+		"slot_center_player_relation":      26,
+		"slot_center_siege_with_belfry":    27,
+		"slot_village_state":               35,
+		"slot_village_infested_by_bandits": 39,
+		"slot_center_original_faction":     61,
+		"slot_center_tavern_bookseller":    98,
+		"slot_village_bound_center":        120,
+		"slot_village_market_town":         121,
+		"slot_center_player_enterprise":    137,
+	},
+	TroopSlot: {
+		"slot_troop_renown":     7,
+		"slot_troop_cur_center": 12,
+	},
+}
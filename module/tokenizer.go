@@ -0,0 +1,151 @@
+package module
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tokenizer splits the text files compiled by the module system into
+// whitespace-separated tokens. The first error is kept and every later read
+// returns a zero value, so parsers check it once after reading a record.
+type tokenizer struct {
+	scanner *bufio.Scanner
+	index   int
+	err     error
+}
+
+func newTokenizer(r io.Reader) *tokenizer {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	scanner.Split(bufio.ScanWords)
+	return &tokenizer{scanner: scanner}
+}
+
+func (t *tokenizer) fail(err error) {
+	if t.err == nil {
+		t.err = fmt.Errorf("token %d: %w", t.index, err)
+	}
+}
+
+func (t *tokenizer) next() string {
+	if t.err != nil {
+		return ""
+	}
+	if !t.scanner.Scan() {
+		err := t.scanner.Err()
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		t.fail(err)
+		return ""
+	}
+	t.index++
+	return t.scanner.Text()
+}
+
+func (t *tokenizer) int() int {
+	token := t.next()
+	if t.err != nil {
+		return 0
+	}
+	i, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		t.fail(err)
+	}
+	return int(i)
+}
+
+func (t *tokenizer) uint64() uint64 {
+	token := t.next()
+	if t.err != nil {
+		return 0
+	}
+	u, err := parseUint64(token)
+	if err != nil {
+		t.fail(err)
+	}
+	return u
+}
+
+// Flags are written as unsigned 64-bit numbers but some modules write them
+// signed, so both are accepted.
+func parseUint64(token string) (uint64, error) {
+	u, err := strconv.ParseUint(token, 10, 64)
+	if err != nil {
+		i, signedErr := strconv.ParseInt(token, 10, 64)
+		if signedErr != nil {
+			return 0, err
+		}
+		u = uint64(i)
+	}
+	return u, nil
+}
+
+func (t *tokenizer) float() float64 {
+	token := t.next()
+	if t.err != nil {
+		return 0
+	}
+	f, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		t.fail(err)
+	}
+	return f
+}
+
+// header reads the "<kind>file version <n>" line that starts every file and
+// returns the version.
+func (t *tokenizer) header(kind string) int {
+	if token := t.next(); t.err == nil && token != kind+"file" {
+		t.fail(fmt.Errorf("expected %sfile, got %q", kind, token))
+	}
+	if token := t.next(); t.err == nil && token != "version" {
+		t.fail(fmt.Errorf("expected version, got %q", token))
+	}
+	return t.int()
+}
+
+// count reads a number of records, rejecting negative ones.
+func (t *tokenizer) count() int {
+	n := t.int()
+	if n < 0 {
+		t.fail(fmt.Errorf("negative count %d", n))
+		return 0
+	}
+	return n
+}
+
+/* The module system replaces spaces in names with underscores. */
+func displayName(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// readOptionalFile is readFile for data files a module may leave out, in which
+// case the zero value is returned.
+func readOptionalFile[T any](path string, parse func(r io.Reader) (T, error)) (T, error) {
+	result, err := readFile(path, parse)
+	if errors.Is(err, fs.ErrNotExist) {
+		return result, nil
+	}
+	return result, err
+}
+
+func readFile[T any](path string, parse func(r io.Reader) (T, error)) (T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer file.Close()
+	result, err := parse(file)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
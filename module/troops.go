@@ -0,0 +1,130 @@
+package module
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// See tf_.* in header_troops.py
+	TfMale                    = 0x00000000
+	TfFemale                  = 0x00000001
+	TfUndead                  = 0x00000002
+	TfHero                    = 0x00000010
+	TfInactive                = 0x00000020
+	TfUnkillable              = 0x00000040
+	TfAllwaysFallDead         = 0x00000080
+	TfNoCaptureAlive          = 0x00000100
+	TfMounted                 = 0x00000400
+	TfIsMerchant              = 0x00001000
+	TfRandomizeFace           = 0x00008000
+	TfGuaranteeBoots          = 0x00100000
+	TfGuaranteeArmor          = 0x00200000
+	TfGuaranteeHelmet         = 0x00400000
+	TfGuaranteeGloves         = 0x00800000
+	TfGuaranteeHorse          = 0x01000000
+	TfGuaranteeShield         = 0x02000000
+	TfGuaranteeRanged         = 0x04000000
+	TfGuaranteePolearm        = 0x08000000
+	TfUnmoveableInPartyWindow = 0x10000000
+
+	troopTypeMask = 0x0000000f
+)
+
+const (
+	numTroopInventorySlots = 64
+	numTroopFaceKeys       = 2
+)
+
+type TroopItem struct {
+	ItemKindId int
+	Modifier   int
+}
+
+// Troop is an entry of troops.txt, i.e. a troop as defined by the module
+// rather than as it is in a savegame.
+type Troop struct {
+	Id               int
+	StringId         string
+	Name             string
+	PluralName       string
+	Image            string
+	Flags            uint64
+	SiteIdAndEntryNo int
+	Reserved         int
+	FactionId        int
+	UpgradeTroopIds  []int
+	Items            []TroopItem
+	Attributes       [4]int
+	Level            int
+	Proficiencies    [7]int
+	Skills           [6]uint32
+	FaceKeys         [numTroopFaceKeys][4]uint64
+}
+
+func (troop Troop) IsHero() bool {
+	return troop.Flags&TfHero != 0
+}
+
+func (troop Troop) IsFemale() bool {
+	return troop.Flags&troopTypeMask == TfFemale
+}
+
+func ParseTroops(r io.Reader) ([]Troop, error) {
+	t := newTokenizer(r)
+	if version := t.header("troops"); t.err == nil && version != 2 {
+		return nil, fmt.Errorf("unsupported troops file version %d", version)
+	}
+	n := t.count()
+	troops := make([]Troop, 0, min(n, 4096))
+	for id := 0; t.err == nil && id < n; id++ {
+		troop := Troop{Id: id}
+		troop.StringId = t.next()
+		troop.Name = displayName(t.next())
+		troop.PluralName = displayName(t.next())
+		// The module system writes the image as it is given, not always a number.
+		troop.Image = t.next()
+		troop.Flags = t.uint64()
+		troop.SiteIdAndEntryNo = t.int()
+		troop.Reserved = t.int()
+		troop.FactionId = t.int()
+		for range 2 {
+			if upgradeTroopId := t.int(); upgradeTroopId > 0 {
+				troop.UpgradeTroopIds = append(troop.UpgradeTroopIds, upgradeTroopId)
+			}
+		}
+		for range numTroopInventorySlots {
+			item := TroopItem{ItemKindId: t.int(), Modifier: t.int()}
+			if item.ItemKindId >= 0 {
+				troop.Items = append(troop.Items, item)
+			}
+		}
+		for i := range troop.Attributes {
+			troop.Attributes[i] = t.int()
+		}
+		troop.Level = t.int()
+		for i := range troop.Proficiencies {
+			troop.Proficiencies[i] = t.int()
+		}
+		for i := range troop.Skills {
+			troop.Skills[i] = uint32(t.uint64())
+		}
+		for i := range troop.FaceKeys {
+			for j := range troop.FaceKeys[i] {
+				troop.FaceKeys[i][j] = t.uint64()
+			}
+		}
+		if t.err != nil {
+			return nil, fmt.Errorf("troop %d (%s): %w", id, troop.StringId, t.err)
+		}
+		troops = append(troops, troop)
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return troops, nil
+}
+
+func ReadTroops(path string) ([]Troop, error) {
+	return readFile(path, ParseTroops)
+}
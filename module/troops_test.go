@@ -0,0 +1,73 @@
+package module
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func testTroopLine(stringId, name string, flags uint64, upgrades [2]int, items ...int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\r\n%s %s %s 0 %d 0 0 3 %d %d\n  ", stringId, name, name+"s", flags, upgrades[0], upgrades[1])
+	for i := range 64 {
+		if i < len(items) {
+			fmt.Fprintf(&b, "%d 0 ", items[i])
+		} else {
+			b.WriteString("-1 0 ")
+		}
+	}
+	b.WriteString("\n  9 8 7 6 12\n 100 90 80 70 60 50 40\n")
+	b.WriteString("4660 0 0 0 0 21 \n  ")
+	b.WriteString("0 0 0 1 0 0 0 2 \n")
+	return b.String()
+}
+
+func TestParseTroops(t *testing.T) {
+	text := "troopsfile version 2\n2 " +
+		testTroopLine("trp_player", "Player", TfHero, [2]int{0, 0}) +
+		testTroopLine("trp_swadian_recruit", "Swadian_Recruit", TfGuaranteeArmor, [2]int{2, 3}, 17, 42)
+	// Mods may give a troop an image that is not a number.
+	text = strings.Replace(text, "Swadian_Recruits 0 ", "Swadian_Recruits tableau_recruit ", 1)
+	troops, err := ParseTroops(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(troops) != 2 {
+		t.Fatalf("expected 2 troops, got %d", len(troops))
+	}
+	player, recruit := troops[0], troops[1]
+	if !player.IsHero() || recruit.IsHero() {
+		t.Error("expected only trp_player to be a hero")
+	}
+	if recruit.Id != 1 || recruit.StringId != "trp_swadian_recruit" || recruit.Name != "Swadian Recruit" {
+		t.Errorf("unexpected recruit identity: %d %s %q", recruit.Id, recruit.StringId, recruit.Name)
+	}
+	if len(recruit.UpgradeTroopIds) != 2 || recruit.UpgradeTroopIds[1] != 3 {
+		t.Errorf("expected upgrades [2 3], got %v", recruit.UpgradeTroopIds)
+	}
+	if len(player.UpgradeTroopIds) != 0 {
+		t.Errorf("expected no upgrades, got %v", player.UpgradeTroopIds)
+	}
+	if len(recruit.Items) != 2 || recruit.Items[1].ItemKindId != 42 {
+		t.Errorf("expected items 17 and 42, got %v", recruit.Items)
+	}
+	if recruit.Image != "tableau_recruit" || player.Image != "0" {
+		t.Errorf("unexpected images %q and %q", player.Image, recruit.Image)
+	}
+	if recruit.FactionId != 3 || recruit.Level != 12 || recruit.Attributes[0] != 9 || recruit.Proficiencies[6] != 40 {
+		t.Errorf("unexpected recruit stats: %+v", recruit)
+	}
+	if recruit.Skills[0] != 4660 || recruit.Skills[5] != 21 {
+		t.Errorf("unexpected skills %v", recruit.Skills)
+	}
+	if recruit.FaceKeys[1][3] != 2 {
+		t.Errorf("unexpected face keys %v", recruit.FaceKeys)
+	}
+}
+
+func TestParseTroopsTruncated(t *testing.T) {
+	text := "troopsfile version 2\n2 " + testTroopLine("trp_player", "Player", TfHero, [2]int{})
+	if _, err := ParseTroops(strings.NewReader(text)); err == nil {
+		t.Error("expected an error for a missing troop")
+	}
+}
@@ -0,0 +1,22 @@
+package module
+
+import (
+	"bufio"
+	"io"
+)
+
+// ParseVariables reads variables.txt, which names the global variables of a
+// savegame in the order they are stored, without their leading $.
+func ParseVariables(r io.Reader) ([]string, error) {
+	var variables []string
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		variables = append(variables, scanner.Text())
+	}
+	return variables, scanner.Err()
+}
+
+func ReadVariables(path string) ([]string, error) {
+	return readFile(path, ParseVariables)
+}
@@ -0,0 +1,34 @@
+package savegame
+
+import (
+	"bytes"
+	"testing"
+)
+
+/* Roughly the size of a late-game Native savegame. */
+func newBenchmarkGame() Game {
+	return newTestGame(1162, 3000, 900)
+}
+
+func BenchmarkDecode(b *testing.B) {
+	game := newBenchmarkGame()
+	data := game.write(LoadOptions{})
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := Decode(bytes.NewReader(data), LoadOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	game := newBenchmarkGame()
+	b.SetBytes(int64(len(game.write(LoadOptions{}))))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		game.write(LoadOptions{})
+	}
+}
@@ -0,0 +1,94 @@
+package savegame
+
+func (s *String) syncCounts() {
+	s.NumChars = Int32(len(s.Chars))
+}
+
+func (note *Note) syncCounts() {
+	note.Text.syncCounts()
+}
+
+func syncNoteCounts(notes *[16]Note) {
+	for i := range notes {
+		notes[i].syncCounts()
+	}
+}
+
+func (party *Party) syncCounts() {
+	party.Id.syncCounts()
+	party.Name.syncCounts()
+	party.NumStacks = Int32(len(party.Stacks))
+	party.ExtraText.syncCounts()
+	party.NumAttachedPartyIds = Int32(len(party.AttachedPartyIds))
+	party.NumParticleSystemIds = Int32(len(party.ParticleSystemIds))
+	syncNoteCounts(&party.Notes)
+	party.NumSlots = Int32(len(party.Slots))
+}
+
+func (troop *Troop) syncCounts() {
+	troop.NumSlots = Int32(len(troop.Slots))
+	syncNoteCounts(&troop.Notes)
+	troop.Name.syncCounts()
+	troop.NamePlural.syncCounts()
+}
+
+// SyncCounts sets every Num.* field of game to the length of the list it
+// describes. Saving does not depend on these fields, but code reading the
+// model after adding or removing list elements may.
+func (game *Game) SyncCounts() {
+	game.Header.SavegameName.syncCounts()
+	game.Header.PlayerName.syncCounts()
+	game.Unused1.syncCounts()
+	game.GameLog.syncCounts()
+	for i := range game.ClassNames {
+		game.ClassNames[i].syncCounts()
+	}
+	game.NumGlobalVariables = Int32(len(game.GlobalVariables))
+	game.NumTriggers = Int32(len(game.Triggers))
+	game.NumSimpleTriggers = Int32(len(game.SimpleTriggers))
+	game.NumQuests = Int32(len(game.Quests))
+	for i := range game.Quests {
+		quest := &game.Quests[i]
+		quest.Title.syncCounts()
+		quest.Text.syncCounts()
+		quest.Giver.syncCounts()
+		syncNoteCounts(&quest.Notes)
+		quest.NumSlots = Int32(len(quest.Slots))
+	}
+	game.NumInfoPages = Int32(len(game.InfoPages))
+	for i := range game.InfoPages {
+		syncNoteCounts(&game.InfoPages[i].Notes)
+	}
+	game.NumSites = Int32(len(game.Sites))
+	for i := range game.Sites {
+		game.Sites[i].NumSlots = Int32(len(game.Sites[i].Slots))
+	}
+	game.NumFactions = Int32(len(game.Factions))
+	for i := range game.Factions {
+		faction := &game.Factions[i]
+		faction.NumSlots = Int32(len(faction.Slots))
+		faction.Name.syncCounts()
+		syncNoteCounts(&faction.Notes)
+	}
+	game.NumMapTracks = Int32(len(game.MapTracks))
+	game.NumPartyTemplates = Int32(len(game.PartyTemplates))
+	for i := range game.PartyTemplates {
+		game.PartyTemplates[i].NumSlots = Int32(len(game.PartyTemplates[i].Slots))
+	}
+	game.NumPartyRecords = Int32(len(game.PartyRecords))
+	for i := range game.PartyRecords {
+		game.PartyRecords[i].Party.syncCounts()
+	}
+	game.NumMapEventRecords = Int32(len(game.MapEventRecords))
+	for i := range game.MapEventRecords {
+		game.MapEventRecords[i].MapEvent.Unused0.syncCounts()
+	}
+	game.NumTroops = Int32(len(game.Troops))
+	for i := range game.Troops {
+		game.Troops[i].syncCounts()
+	}
+	game.NumItemKinds = Int32(len(game.ItemKinds))
+	for i := range game.ItemKinds {
+		game.ItemKinds[i].NumSlots = Int32(len(game.ItemKinds[i].Slots))
+	}
+}
@@ -0,0 +1,125 @@
+package savegame
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeError reports where and why a savegame could not be decoded.
+type DecodeError struct {
+	// Offset is the byte offset at which decoding stopped.
+	Offset int64
+	// Section is the path of the model element being read, e.g.
+	// "PartyRecords[412].Party.Stacks[3]".
+	Section string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Section == "" {
+		return fmt.Sprintf("savegame: offset %d: %v", e.Offset, e.Err)
+	}
+	return fmt.Sprintf("savegame: offset %d (%s): %v", e.Offset, e.Section, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+type section struct {
+	name  string
+	index int
+}
+
+// decoder is a cursor over a savegame held in memory. Values are decoded
+// straight from the byte slice rather than through encoding/binary's
+// reflection, as a late-game savegame holds millions of them.
+type decoder struct {
+	data     []byte
+	offset   int
+	sections []section
+	options  LoadOptions
+	// capabilities are those of the game version read from the header.
+	capabilities Capabilities
+}
+
+// Sections are only formatted when an error occurs, so entering one is cheap.
+// An index of -1 denotes a struct field rather than a list element.
+func (d *decoder) enter(name string, index int) {
+	d.sections = append(d.sections, section{name, index})
+}
+
+func (d *decoder) leave() {
+	d.sections = d.sections[:len(d.sections)-1]
+}
+
+func (d *decoder) section() string {
+	var b strings.Builder
+	for i, s := range d.sections {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(s.name)
+		if s.index >= 0 {
+			fmt.Fprintf(&b, "[%d]", s.index)
+		}
+	}
+	return b.String()
+}
+
+// bytes returns the next n bytes. The result aliases the decoded data but has
+// its capacity clipped, so appending to it never overwrites what follows.
+func (d *decoder) bytes(n int) []byte {
+	if n > len(d.data)-d.offset {
+		d.fail(io.ErrUnexpectedEOF)
+	}
+	b := d.data[d.offset : d.offset+n : d.offset+n]
+	d.offset += n
+	return b
+}
+
+// count checks a length prefix read from the savegame. Every element takes at
+// least minSize bytes, so a count the remaining input cannot hold is rejected
+// before anything is allocated for it.
+func (d *decoder) count(n Int32, minSize int) int {
+	if n < 0 {
+		d.fail(fmt.Errorf("negative count %d", n))
+	}
+	if remaining := len(d.data) - d.offset; int(n) > remaining/minSize {
+		d.fail(fmt.Errorf("count %d does not fit in the remaining %d bytes: %w", n, remaining, io.ErrUnexpectedEOF))
+	}
+	return int(n)
+}
+
+func (d *decoder) bool() bool {
+	return d.bytes(1)[0] != 0
+}
+
+func (d *decoder) uint32() uint32 {
+	return binary.LittleEndian.Uint32(d.bytes(4))
+}
+
+func (d *decoder) uint64() uint64 {
+	return binary.LittleEndian.Uint64(d.bytes(8))
+}
+
+func (d *decoder) fail(err error) {
+	panic(&DecodeError{Offset: int64(d.offset), Section: d.section(), Err: err})
+}
+
+// recover turns a *DecodeError raised while decoding into the returned
+// error. Any other panic is a bug in the decoder and is raised again. It must
+// be deferred directly by the function that started decoding.
+func (d *decoder) recover(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	decodeErr, ok := r.(*DecodeError)
+	if !ok {
+		panic(r)
+	}
+	*err = decodeErr
+}
@@ -0,0 +1,83 @@
+package savegame
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestDecodeTruncated(t *testing.T) {
+	var buf []byte
+	buf = binary.LittleEndian.AppendUint32(buf, 0x52445257)
+	buf = binary.LittleEndian.AppendUint32(buf, 1162)
+	buf = binary.LittleEndian.AppendUint32(buf, 0)
+	buf = binary.LittleEndian.AppendUint32(buf, 10)
+	buf = append(buf, "sg0"...)
+
+	_, err := Decode(bytes.NewReader(buf), LoadOptions{})
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %v", err)
+	}
+	if decodeErr.Offset != 16 {
+		t.Errorf("expected offset 16, got %d", decodeErr.Offset)
+	}
+	if decodeErr.Section != "Header" {
+		t.Errorf("expected section Header, got %q", decodeErr.Section)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", decodeErr.Err)
+	}
+}
+
+func TestDecodeBadMagicNumber(t *testing.T) {
+	_, err := Decode(bytes.NewReader([]byte("not a savegame")), LoadOptions{})
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %v", err)
+	}
+	if decodeErr.Offset != 4 {
+		t.Errorf("expected offset 4, got %d", decodeErr.Offset)
+	}
+	if !errors.Is(err, ErrNotWarband) {
+		t.Errorf("expected ErrNotWarband, got %v", decodeErr.Err)
+	}
+}
+
+func TestDecodeUnsupportedVersion(t *testing.T) {
+	for _, version := range []Int32{-1, MaxGameVersion + 1} {
+		game := newTestGame(MaxGameVersion, 1, 1)
+		data := game.write(LoadOptions{})
+		binary.LittleEndian.PutUint32(data[4:], uint32(version))
+
+		_, err := Decode(bytes.NewReader(data), LoadOptions{})
+		var versionErr *UnsupportedVersionError
+		if !errors.As(err, &versionErr) {
+			t.Fatalf("expected *UnsupportedVersionError for version %d, got %v", version, err)
+		}
+		if versionErr.Version != version {
+			t.Errorf("expected version %d, got %d", version, versionErr.Version)
+		}
+	}
+}
+
+func TestDecodeRejectsOversizedCount(t *testing.T) {
+	game := newTestGame(1162, 3, 3)
+	game.ClassNames[8] = testString("class8")
+	data := game.write(LoadOptions{})
+	// The number of global variables follows the last class name; corrupt it
+	// to claim far more than the input holds.
+	offset := bytes.Index(data, []byte("class8")) + len("class8")
+	binary.LittleEndian.PutUint32(data[offset:], 0x7fffffff)
+
+	_, err := Decode(bytes.NewReader(data), LoadOptions{})
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %v", err)
+	}
+	if decodeErr.Offset != int64(offset+4) {
+		t.Errorf("expected offset %d, got %d", offset+4, decodeErr.Offset)
+	}
+}
@@ -0,0 +1,182 @@
+package savegame
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Change is a difference between two games found by Diff.
+type Change struct {
+	// Path is the model element that changed, e.g.
+	// "PartyRecords[23].Party.Slots[26]".
+	Path string
+	// Name is the name of the party, troop or faction Path is in, if known.
+	Name string
+	// Old and New are the values before and after the change, followed by the
+	// name of the party, troop or faction they refer to if known. Old is empty
+	// for an added list element and New for a removed one; elements that are
+	// not single values are given by their type, e.g. "PartyStack".
+	Old, New string
+}
+
+func (change Change) String() string {
+	path := change.Path
+	if change.Name != "" {
+		path += " (" + change.Name + ")"
+	}
+	switch {
+	case change.Old == "":
+		return path + ": added " + change.New
+	case change.New == "":
+		return path + ": removed " + change.Old
+	}
+	return path + ": " + change.Old + " → " + change.New
+}
+
+// Diff returns the fields of b that differ from a, in the order of the model.
+// Lists are compared element by element, with elements past the end of the
+// shorter list reported as added or removed as a whole. A party or map event
+// record that was added or removed is reported as a change of Valid only.
+//
+// Parties and factions are named by their names in the games, and troops by
+// theirs if renamed or else by those of the module's troops.txt.
+func Diff(a, b Game, options LoadOptions) []Change {
+	d := differ{a: &a, b: &b, options: options}
+	d.diff("", "", reflect.ValueOf(a), reflect.ValueOf(b))
+	return d.changes
+}
+
+type differ struct {
+	a, b    *Game
+	options LoadOptions
+	changes []Change
+}
+
+var (
+	stringType = reflect.TypeFor[String]()
+	floatType  = reflect.TypeFor[Float]()
+)
+
+func (d *differ) diff(path string, name string, a reflect.Value, b reflect.Value) {
+	switch {
+	case a.Type() == stringType:
+		if !bytes.Equal(a.Interface().(String).Chars, b.Interface().(String).Chars) {
+			d.report(path, name, a, b)
+		}
+	case a.Kind() == reflect.Struct:
+		if valid := a.FieldByName("Valid"); valid.IsValid() && !valid.Equal(b.FieldByName("Valid")) {
+			d.diff(path+".Valid", name, valid, b.FieldByName("Valid"))
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			fieldPath := a.Type().Field(i).Name
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			d.diff(fieldPath, name, a.Field(i), b.Field(i))
+		}
+	case a.Kind() == reflect.Slice || a.Kind() == reflect.Array:
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			elementName := name
+			if path == "PartyRecords" || path == "Troops" || path == "Factions" {
+				elementName = d.objectName(path, i)
+			}
+			switch {
+			case i >= a.Len():
+				d.changes = append(d.changes, Change{Path: elementPath, Name: elementName, New: d.format(d.b, elementPath, b.Index(i))})
+			case i >= b.Len():
+				d.changes = append(d.changes, Change{Path: elementPath, Name: elementName, Old: d.format(d.a, elementPath, a.Index(i))})
+			default:
+				d.diff(elementPath, elementName, a.Index(i), b.Index(i))
+			}
+		}
+	case a.Type() == floatType:
+		// Savegames hold NaNs, which are never == to themselves.
+		if math.Float32bits(float32(a.Interface().(Float))) != math.Float32bits(float32(b.Interface().(Float))) {
+			d.report(path, name, a, b)
+		}
+	case !a.Equal(b):
+		d.report(path, name, a, b)
+	}
+}
+
+func (d *differ) report(path string, name string, a reflect.Value, b reflect.Value) {
+	d.changes = append(d.changes, Change{
+		Path: path,
+		Name: name,
+		Old:  d.format(d.a, path, a),
+		New:  d.format(d.b, path, b),
+	})
+}
+
+func (d *differ) format(game *Game, path string, value reflect.Value) string {
+	if s, ok := value.Interface().(String); ok {
+		return strconv.Quote(s.String())
+	}
+	switch value.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array:
+		return value.Type().Name()
+	}
+	formatted := fmt.Sprint(value.Interface())
+	if list := idListOf(path); list != "" && value.CanInt() {
+		if name := objectName(game, d.options, list, int(value.Int())); name != "" {
+			formatted += " (" + name + ")"
+		}
+	}
+	return formatted
+}
+
+/* The name of the other game is used for objects missing from one of them. */
+func (d *differ) objectName(list string, i int) string {
+	if name := objectName(d.b, d.options, list, i); name != "" {
+		return name
+	}
+	return objectName(d.a, d.options, list, i)
+}
+
+// idListOf returns the list that the ids stored at path index, judging by
+// the field name, e.g. "Troops" for "PartyRecords[3].Party.Stacks[0].TroopId".
+func idListOf(path string) string {
+	field := path[strings.LastIndex(path, ".")+1:]
+	if i := strings.IndexByte(field, '['); i >= 0 {
+		field = field[:i]
+	}
+	switch {
+	case strings.HasSuffix(field, "PartyId"), strings.HasSuffix(field, "PartyIds"),
+		field == "EncounteredParty1Id", field == "EncounteredParty2Id":
+		return "PartyRecords"
+	case strings.HasSuffix(field, "TroopId"):
+		return "Troops"
+	case field == "FactionId":
+		return "Factions"
+	}
+	return ""
+}
+
+func objectName(game *Game, options LoadOptions, list string, i int) string {
+	switch list {
+	case "PartyRecords":
+		if i >= 0 && i < len(game.PartyRecords) && game.PartyRecords[i].Valid == 1 {
+			return game.PartyRecords[i].Party.Name.String()
+		}
+	case "Troops":
+		if i >= 0 && i < len(game.Troops) && game.Troops[i].Renamed {
+			return game.Troops[i].Name.String()
+		}
+		if options.Module != nil {
+			if troop, ok := options.Module.Troop(i); ok {
+				return troop.Name
+			}
+		}
+	case "Factions":
+		if i >= 0 && i < len(game.Factions) {
+			return game.Factions[i].Name.String()
+		}
+	}
+	return ""
+}
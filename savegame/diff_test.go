@@ -0,0 +1,51 @@
+package savegame
+
+import (
+	"math"
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestDiff(t *testing.T) {
+	troops := make([]module.Troop, 3)
+	troops[1].Name = "Swadian Recruit"
+	options := LoadOptions{Module: &module.Module{Troops: troops}}
+
+	a := newTestGame(1162, 8, 3)
+	b := newTestGame(1162, 8, 3)
+	a.GlobalHazeAmount = Float(math.NaN())
+	b.GlobalHazeAmount = Float(math.NaN())
+	a.GlobalCloudAmount = Float(math.NaN())
+	b.Factions[2].Name = testString("Kingdom of Swadia")
+	b.PartyRecords[3].Party.FactionId = 2
+	b.PartyRecords[3].Party.Slots[1] = -3
+	b.PartyRecords[6].Valid = 1
+	b.Troops[0].Proficiencies[2] = 95
+	b.Troops[1].Slots = append(b.Troops[1].Slots, 7)
+	b.Troops[1].NumSlots = 5
+
+	expected := []string{
+		`GlobalCloudAmount: NaN → 0.5`,
+		`Factions[2].Name (Kingdom of Swadia): "Faction" → "Kingdom of Swadia"`,
+		`PartyRecords[3].Party.FactionId (Test Party): 1 (Faction) → 2 (Kingdom of Swadia)`,
+		`PartyRecords[3].Party.Slots[1] (Test Party): 301 → -3`,
+		`PartyRecords[6].Valid: 0 → 1`,
+		`Troops[0].Proficiencies[2] (Hero): 80 → 95`,
+		`Troops[1].NumSlots (Swadian Recruit): 4 → 5`,
+		`Troops[1].Slots[4] (Swadian Recruit): added 7`,
+	}
+	changes := Diff(a, b, options)
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %v", len(expected), len(changes), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("expected change %q, got %q", expected[i], change)
+		}
+	}
+
+	if changes := Diff(a, a, options); len(changes) != 0 {
+		t.Errorf("expected no changes between a game and itself, got %v", changes)
+	}
+}
@@ -0,0 +1,157 @@
+package savegame
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FaceCode is a face as stored in Troop.FaceKeys and as shown by the in-game
+// face editor, e.g. "0x000000018000000136db6db6db6db6db00000000001db6db0000000000000000",
+// whose first 16 hex digits are the first key.
+//
+// The layout of the keys is not documented by the game; the one used here is
+// the community's reading of face editor output. The first key holds the
+// FaceFields in 6 bits each. The second and third keys hold 21 morphs of 3
+// bits each; the fourth key is unused.
+type FaceCode [4]UInt64
+
+// FaceField is one of the 6-bit fields in the first key of a FaceCode.
+type FaceField int
+
+const (
+	FaceHair FaceField = iota
+	FaceBeard
+	FaceTexture
+	FaceHairTexture
+	FaceHairColor
+	FaceAge
+	FaceSkinColor
+	numFaceFields
+)
+
+var faceFieldNames = [numFaceFields]string{
+	FaceHair:        "hair",
+	FaceBeard:       "beard",
+	FaceTexture:     "face texture",
+	FaceHairTexture: "hair texture",
+	FaceHairColor:   "hair color",
+	FaceAge:         "age",
+	FaceSkinColor:   "skin color",
+}
+
+func (field FaceField) String() string {
+	if field < 0 || field >= numFaceFields {
+		return fmt.Sprintf("face field %d", int(field))
+	}
+	return faceFieldNames[field]
+}
+
+const (
+	faceFieldBits    = 6
+	faceFieldMax     = 1<<faceFieldBits - 1
+	faceMorphBits    = 3
+	faceMorphMax     = 1<<faceMorphBits - 1
+	faceMorphsPerKey = 64 / faceMorphBits
+	// NumFaceMorphs is the number of morphs in the second and third keys.
+	NumFaceMorphs = 2 * faceMorphsPerKey
+)
+
+// ParseFaceCode parses a face code as written by the face editor: 64 hex
+// digits, optionally preceded by 0x.
+func ParseFaceCode(s string) (code FaceCode, err error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(digits) != 64 {
+		return code, fmt.Errorf("savegame: face code %q does not have 64 hex digits", s)
+	}
+	for i := range code {
+		key, err := strconv.ParseUint(digits[i*16:(i+1)*16], 16, 64)
+		if err != nil {
+			return code, fmt.Errorf("savegame: face code %q: %w", s, err)
+		}
+		code[i] = UInt64(key)
+	}
+	return code, nil
+}
+
+func (code FaceCode) String() string {
+	return fmt.Sprintf("0x%016x%016x%016x%016x", uint64(code[0]), uint64(code[1]), uint64(code[2]), uint64(code[3]))
+}
+
+func (code FaceCode) Field(field FaceField) int {
+	return int(code[0]>>(int(field)*faceFieldBits)) & faceFieldMax
+}
+
+// SetField sets a field to a value between 0 and 63. The range of values the
+// game uses depends on the skin; e.g. hair colors and ages are scaled to it.
+func (code *FaceCode) SetField(field FaceField, value int) error {
+	if field < 0 || field >= numFaceFields {
+		return fmt.Errorf("savegame: invalid face field %d", int(field))
+	}
+	if value < 0 || value > faceFieldMax {
+		return fmt.Errorf("savegame: %s %d is not between 0 and %d", field, value, faceFieldMax)
+	}
+	shift := int(field) * faceFieldBits
+	code[0] = code[0]&^(faceFieldMax<<shift) | UInt64(value)<<shift
+	return nil
+}
+
+func (code FaceCode) Morph(morph int) int {
+	if morph < 0 || morph >= NumFaceMorphs {
+		return 0
+	}
+	key := code[1+morph/faceMorphsPerKey]
+	return int(key>>(morph%faceMorphsPerKey*faceMorphBits)) & faceMorphMax
+}
+
+// SetMorph sets a morph to a value between 0 and 7.
+func (code *FaceCode) SetMorph(morph int, value int) error {
+	if morph < 0 || morph >= NumFaceMorphs {
+		return fmt.Errorf("savegame: invalid face morph %d", morph)
+	}
+	if value < 0 || value > faceMorphMax {
+		return fmt.Errorf("savegame: face morph %d value %d is not between 0 and %d", morph, value, faceMorphMax)
+	}
+	shift := morph % faceMorphsPerKey * faceMorphBits
+	key := &code[1+morph/faceMorphsPerKey]
+	*key = *key&^(faceMorphMax<<shift) | UInt64(value)<<shift
+	return nil
+}
+
+func (troop Troop) Face() FaceCode {
+	return FaceCode(troop.FaceKeys)
+}
+
+func (troop *Troop) SetFace(code FaceCode) {
+	troop.FaceKeys = code
+}
+
+// PlayerFace returns the face of the player, troop 0.
+func (game Game) PlayerFace() (FaceCode, error) {
+	if len(game.Troops) == 0 {
+		return FaceCode{}, fmt.Errorf("savegame: the player troop does not exist")
+	}
+	return game.Troops[0].Face(), nil
+}
+
+// SetPlayerFace sets the face of the player. Game.PlayerFaceKeys0 and
+// PlayerFaceKeys1 are left alone, as it is not known how they relate to it.
+func (game *Game) SetPlayerFace(code FaceCode) error {
+	if len(game.Troops) == 0 {
+		return fmt.Errorf("savegame: the player troop does not exist")
+	}
+	game.Troops[0].SetFace(code)
+	return nil
+}
+
+// CopyFace gives the troop toTroopId the face of the troop fromTroopId, e.g.
+// to give a companion the face of a lord.
+func (game *Game) CopyFace(fromTroopId int, toTroopId int) error {
+	for _, troopId := range []int{fromTroopId, toTroopId} {
+		if troopId < 0 || troopId >= len(game.Troops) {
+			return fmt.Errorf("savegame: troop %d does not exist", troopId)
+		}
+	}
+	game.Troops[toTroopId].SetFace(game.Troops[fromTroopId].Face())
+	return nil
+}
@@ -0,0 +1,77 @@
+package savegame
+
+import "testing"
+
+func TestFaceCode(t *testing.T) {
+	const s = "0x000000018000000136db6db6db6db6db00000000001db6db0000000000000000"
+	code, err := ParseFaceCode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code[0] != 0x0000000180000001 || code[2] != 0x00000000001db6db {
+		t.Errorf("unexpected keys %x", code)
+	}
+	if code.String() != s {
+		t.Errorf("expected %s, got %s", s, code)
+	}
+	if hair := code.Field(FaceHair); hair != 1 {
+		t.Errorf("expected hair 1, got %d", hair)
+	}
+	if age := code.Field(FaceAge); age != 6 {
+		t.Errorf("expected age 6, got %d", age)
+	}
+	if morph := code.Morph(0); morph != 3 {
+		t.Errorf("expected morph 0 to be 3, got %d", morph)
+	}
+
+	if err := code.SetField(FaceBeard, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := code.SetMorph(22, 7); err != nil {
+		t.Fatal(err)
+	}
+	if code.Field(FaceBeard) != 5 || code.Field(FaceHair) != 1 || code.Morph(22) != 7 || code.Morph(21) != 3 {
+		t.Errorf("expected only the beard and morph 22 to change, got %s", code)
+	}
+	if err := code.SetField(FaceSkinColor, 64); err == nil {
+		t.Error("expected an error for a skin color above 63")
+	}
+	if err := code.SetMorph(NumFaceMorphs, 0); err == nil {
+		t.Error("expected an error for an invalid morph")
+	}
+	for _, invalid := range []string{"0x1234", s[:65] + "g"} {
+		if _, err := ParseFaceCode(invalid); err == nil {
+			t.Errorf("expected an error for face code %q", invalid)
+		}
+	}
+}
+
+func TestCopyFace(t *testing.T) {
+	game := newTestGame(MaxGameVersion, 1, 3)
+	game.Troops[2].FaceKeys = [4]UInt64{1, 2, 3, 4}
+	if err := game.CopyFace(2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if face, err := game.PlayerFace(); err != nil || face != (FaceCode{1, 2, 3, 4}) {
+		t.Errorf("expected the player to get troop 2's face, got %s, %v", face, err)
+	}
+	if game.PlayerFaceKeys0 != 7 || game.PlayerFaceKeys1 != 8 {
+		t.Errorf("expected PlayerFaceKeys0 and PlayerFaceKeys1 to be left alone, got %d and %d", game.PlayerFaceKeys0, game.PlayerFaceKeys1)
+	}
+	if err := game.CopyFace(3, 0); err == nil {
+		t.Error("expected an error copying the face of a troop that does not exist")
+	}
+	if err := game.CopyFace(2, -1); err == nil {
+		t.Error("expected an error copying a face to a troop that does not exist")
+	}
+}
+
+func TestPlayerFaceWithoutTroops(t *testing.T) {
+	var game Game
+	if _, err := game.PlayerFace(); err == nil {
+		t.Error("expected an error getting the face of a missing player")
+	}
+	if err := game.SetPlayerFace(FaceCode{1, 2, 3, 4}); err == nil {
+		t.Error("expected an error setting the face of a missing player")
+	}
+}
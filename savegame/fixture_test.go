@@ -0,0 +1,273 @@
+package savegame
+
+// One version from each range the savegame layout depends on, and the newest.
+var testGameVersions = []struct {
+	name    string
+	version Int32
+}{
+	{"pre-900", 800},
+	{"900-999", 950},
+	{"1000-1019", 1000},
+	{"1020-1136", 1020},
+	{"1137-1161", 1137},
+	{"1162-1174", 1162},
+	{"newest", MaxGameVersion},
+}
+
+func testString(s string) String {
+	return String{NumChars: Int32(len(s)), Chars: []byte(s)}
+}
+
+func testSlots(n int, seed int) []Int64 {
+	slots := make([]Int64, n)
+	for i := range slots {
+		slots[i] = Int64(seed*100 + i)
+	}
+	return slots
+}
+
+func testNotes(text string) (notes [16]Note) {
+	for i := range notes {
+		notes[i].Text = testString("")
+		notes[i].TableauMaterialId = -1
+	}
+	notes[0] = Note{Text: testString(text), Value: 1, TableauMaterialId: -1, Available: true}
+	return notes
+}
+
+func testParty(id int, capabilities Capabilities, stacks []PartyStack) Party {
+	party := Party{
+		Id:                   testString("p_test"),
+		Name:                 testString("Test Party"),
+		Flags:                0x100,
+		MenuId:               -1,
+		PartyTemplateId:      1,
+		FactionId:            1,
+		Personality:          0x00000102,
+		InitialPositionX:     Float(id),
+		InitialPositionY:     -Float(id),
+		PositionX:            Float(id) / 2,
+		PositionY:            -Float(id) / 2,
+		NumStacks:            Int32(len(stacks)),
+		Stacks:               stacks,
+		Bearing:              0.5,
+		ExtraText:            testString(""),
+		Morale:               60,
+		Hunger:               1.5,
+		PatrolRadius:         2,
+		Initiative:           1,
+		Helpfulness:          1,
+		LabelVisible:         1,
+		BanditAttraction:     0.25,
+		IgnorePlayerTimer:    -1,
+		BannerMapIconId:      -1,
+		AttachedToPartyId:    -1,
+		NumAttachedPartyIds:  1,
+		AttachedPartyIds:     []Int32{0},
+		NumParticleSystemIds: 0,
+		ParticleSystemIds:    []Int32{},
+		Notes:                testNotes("party note"),
+		NumSlots:             8,
+		Slots:                testSlots(8, id),
+	}
+	if capabilities.HasMarshall {
+		party.Marshall = 3
+	}
+	if capabilities.HasExtraMapIcon {
+		party.ExtraMapIconId = 2
+		party.ExtraMapIconUpDownDistance = 0.1
+		party.ExtraMapIconUpDownFrequency = 0.2
+		party.ExtraMapIconRotateFrequency = 0.3
+		party.ExtraMapIconFadeFrequency = 0.4
+	}
+	if capabilities.HasPartyUnused2 {
+		party.Unused2 = 7
+	}
+	return party
+}
+
+func testTroop(id int, hero bool) Troop {
+	troop := Troop{
+		NumSlots:      4,
+		Slots:         testSlots(4, id),
+		Attributes:    [4]Int32{9, 8, 7, 6},
+		Proficiencies: [7]Float{100, 90, 80, 70, 60, 50, 40},
+		Skills:        [6]UInt32{0x12345678, 0, 0, 0, 0, 0x00000321},
+		Notes:         testNotes("troop note"),
+		Level:         Int32(id % 30),
+		ClassNo:       Int32(id % 9),
+	}
+	if hero {
+		troop.Flags = heroFlag
+		troop.Gold = 1000
+		troop.Experience = 5000
+		troop.Health = 1
+		troop.FactionId = 1
+		for i := range troop.InventoryItems {
+			troop.InventoryItems[i].ItemKindId = -1
+		}
+		for i := range troop.EquippedItems {
+			troop.EquippedItems[i] = Item{ItemKindId: Int32(i % 3), ItemFlags: 0}
+		}
+		troop.FaceKeys = [4]UInt64{1, 2, 3, 4}
+		troop.Renamed = id == 0
+		if troop.Renamed {
+			troop.Name = testString("Hero")
+			troop.NamePlural = testString("Heroes")
+		}
+	}
+	return troop
+}
+
+// newTestGame builds a consistent Game of the given version. Fields that the
+// version does not store are left zero, so the game survives being written
+// and read back unchanged. newTestGame(version, 1, 1) is the smallest valid
+// game; more party records and troops approximate a late-game savegame.
+func newTestGame(gameVersion Int32, numParties int, numTroops int) Game {
+	capabilities, err := VersionCapabilities(gameVersion)
+	if err != nil {
+		panic(err)
+	}
+	game := Game{
+		Header: Header{
+			MagicNumber:   magicNumber,
+			GameVersion:   gameVersion,
+			ModuleVersion: 1,
+			SavegameName:  testString("sg00"),
+			PlayerName:    testString("Player"),
+			PlayerLevel:   12,
+			Date:          42.5,
+		},
+		GameTime:                    123456789,
+		RandomSeed:                  4,
+		SaveMode:                    1,
+		DateTimer:                   987654321,
+		Hour:                        13,
+		Day:                         45,
+		Week:                        6,
+		Month:                       2,
+		Year:                        1257,
+		GlobalCloudAmount:           0.5,
+		GlobalHazeAmount:            0.25,
+		AverageDifficulty:           0.75,
+		AverageDifficultyPeriod:     10,
+		Unused1:                     testString(""),
+		EncounteredParty1Id:         -1,
+		EncounteredParty2Id:         -1,
+		CurrentMenuId:               3,
+		CurrentSiteId:               -1,
+		CurrentEntryNo:              -1,
+		CurrentMissionTemplateId:    -1,
+		PartyCreationMinRandomValue: 0,
+		PartyCreationMaxRandomValue: 100,
+		GameLog:                     testString("log"),
+		Unused3:                     [6]Int32{1, 2, 3, 4, 5, 6},
+		RestPeriod:                  1,
+		RestTimeSpeed:               2,
+		NumGlobalVariables:          16,
+		GlobalVariables:             testSlots(16, 1),
+		NumTriggers:                 2,
+		Triggers:                    []Trigger{{Status: 1, CheckTimer: 2, DelayTimer: 3, RearmTimer: 4}, {}},
+		NumSimpleTriggers:           1,
+		SimpleTriggers:              []SimpleTrigger{{CheckTimer: 5}},
+		NumQuests:                   1,
+		Quests: []Quest{{
+			GiverTroopId: -1,
+			StartDate:    1,
+			Title:        testString("Quest"),
+			Text:         testString("Quest text"),
+			Giver:        testString("Nobody"),
+			Notes:        testNotes("quest note"),
+			NumSlots:     3,
+			Slots:        testSlots(3, 2),
+		}},
+		NumInfoPages:    1,
+		InfoPages:       []InfoPage{{Notes: testNotes("info page")}},
+		NumSites:        2,
+		Sites:           []Site{{NumSlots: 1, Slots: testSlots(1, 3)}, {NumSlots: 0, Slots: []Int64{}}},
+		NumMapTracks:    1,
+		MapTracks:       []MapTrack{{PositionX: 1, PositionY: 2, PositionZ: 3, Rotation: 4, Age: 5, Flags: 6}},
+		PlayerFaceKeys0: 7,
+		PlayerFaceKeys1: 8,
+		PlayerKillCount: 9,
+	}
+	if capabilities.HasCombatSettings {
+		game.CombatDifficulty = 1
+		game.CombatDifficultyFriendlies = 2
+		game.ReduceCombatAi = 1
+		game.ReduceCampaignAi = 1
+		game.CombatSpeed = 2
+	}
+	for i := range game.ClassNames {
+		game.ClassNames[i] = testString("")
+	}
+
+	const numFactions = 4
+	game.NumFactions = numFactions
+	game.Factions = make([]Faction, numFactions)
+	for i := range game.Factions {
+		relations := make([]Float, numFactions)
+		for j := range relations {
+			relations[j] = Float(i-j) / 10
+		}
+		game.Factions[i] = Faction{
+			NumSlots:  2,
+			Slots:     testSlots(2, i),
+			Relations: relations,
+			Name:      testString("Faction"),
+			Color:     0xFFAA0000,
+			Notes:     testNotes("faction note"),
+		}
+	}
+
+	game.NumPartyTemplates = 2
+	game.PartyTemplates = []PartyTemplate{
+		{NumPartiesCreated: 1, NumSlots: 1, Slots: testSlots(1, 4)},
+		{NumPartiesDestroyed: 2, NumPartiesDestroyedByPlayer: 1, NumSlots: 0, Slots: []Int64{}},
+	}
+
+	game.NumPartyRecords = Int32(numParties)
+	game.NumPartiesCreated = Int32(numParties)
+	game.PartyRecords = make([]PartyRecord, numParties)
+	for i := range game.PartyRecords {
+		if i%7 == 6 {
+			continue // An invalid record, as left behind by a destroyed party.
+		}
+		stacks := []PartyStack{}
+		if i == 0 {
+			stacks = append(stacks, PartyStack{TroopId: 0, NumTroops: 1})
+		}
+		for troopId := 1; troopId < min(numTroops, 3); troopId++ {
+			stacks = append(stacks, PartyStack{TroopId: Int32(troopId), NumTroops: Int32(10 * troopId), NumWoundedTroops: Int32(i % 3)})
+		}
+		game.PartyRecords[i] = PartyRecord{Valid: 1, RawId: Int32(i), Id: Int32(i), Party: testParty(i, capabilities, stacks)}
+	}
+	game.PlayerPartyStackAdditionalInfo = make([]PlayerPartyStack, len(game.PartyRecords[0].Party.Stacks))
+	for i := range game.PlayerPartyStackAdditionalInfo {
+		if hasAdditionalInfo(game.PartyRecords[0].Party, i, LoadOptions{}) {
+			info := &game.PlayerPartyStackAdditionalInfo[i]
+			info.Experience = Float(i * 10)
+			info.NumUpgradeable = Int32(i)
+			for j := range info.TroopDnas {
+				info.TroopDnas[j] = Int32(i*32 + j)
+			}
+		}
+	}
+
+	game.NumMapEventRecords = 2
+	game.NumMapEventsCreated = 2
+	game.MapEventRecords = []MapEventRecord{
+		{Valid: 1, Id: 0, MapEvent: MapEvent{Unused0: testString(""), Type: 1, AttackerPartyId: Int32(numParties - 1), DefenderPartyId: 0, NextBattleSimulation: 1}},
+		{},
+	}
+
+	game.NumTroops = Int32(numTroops)
+	game.Troops = make([]Troop, numTroops)
+	for i := range game.Troops {
+		game.Troops[i] = testTroop(i, i == 0 || (i >= 194 && i < 463))
+	}
+
+	game.NumItemKinds = 3
+	game.ItemKinds = []ItemKind{{NumSlots: 1, Slots: testSlots(1, 5)}, {NumSlots: 0, Slots: []Int64{}}, {NumSlots: 2, Slots: testSlots(2, 6)}}
+	return game
+}
@@ -0,0 +1,39 @@
+package savegame
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func FuzzDecode(f *testing.F) {
+	for _, v := range testGameVersions {
+		minimal := newTestGame(v.version, 1, 1)
+		f.Add(minimal.write(LoadOptions{}))
+		small := newTestGame(v.version, 3, 3)
+		f.Add(small.write(LoadOptions{}))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		game, err := Decode(bytes.NewReader(data), LoadOptions{})
+		if err != nil {
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected *DecodeError, got %v", err)
+			}
+			return
+		}
+		// Whatever was decoded must survive a round trip unchanged. Diff
+		// rather than reflect.DeepEqual, since decoded floats may be NaNs.
+		var encoded bytes.Buffer
+		if err := Encode(&encoded, game, SaveOptions{SkipValidation: true}); err != nil {
+			t.Fatalf("encode decoded game: %v", err)
+		}
+		decoded, err := Decode(bytes.NewReader(encoded.Bytes()), LoadOptions{})
+		if err != nil {
+			t.Fatalf("decode re-encoded game: %v", err)
+		}
+		if changes := Diff(game, decoded, LoadOptions{}); len(changes) > 0 {
+			t.Fatalf("round trip changed %d values, first %s", len(changes), changes[0])
+		}
+	})
+}
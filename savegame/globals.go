@@ -0,0 +1,69 @@
+package savegame
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GlobalVarIndex returns the index in Game.GlobalVariables of the named
+// global variable of the module, as listed in its variables.txt. The leading
+// $ of the name is optional.
+func (options LoadOptions) GlobalVarIndex(name string) (index int, ok bool) {
+	if options.Module == nil {
+		return 0, false
+	}
+	index = slices.Index(options.Module.Variables, strings.TrimPrefix(name, "$"))
+	return index, index >= 0
+}
+
+func globalVarIndex(options LoadOptions, name string) (int, error) {
+	if options.Module == nil || len(options.Module.Variables) == 0 {
+		return 0, fmt.Errorf("savegame: cannot look up global variable %q without the module's variables.txt", name)
+	}
+	index, ok := options.GlobalVarIndex(name)
+	if !ok {
+		return 0, fmt.Errorf("savegame: unknown global variable %q", name)
+	}
+	return index, nil
+}
+
+// GlobalVar returns the value of the named global variable, e.g.
+// "player_honor".
+func (game Game) GlobalVar(options LoadOptions, name string) (Int64, error) {
+	index, err := globalVarIndex(options, name)
+	if err != nil || index >= len(game.GlobalVariables) {
+		return 0, err
+	}
+	return game.GlobalVariables[index], nil
+}
+
+// SetGlobalVar sets the value of the named global variable, adding variables
+// up to it if needed.
+func (game *Game) SetGlobalVar(options LoadOptions, name string, value Int64) error {
+	index, err := globalVarIndex(options, name)
+	if err != nil {
+		return err
+	}
+	for len(game.GlobalVariables) <= index {
+		game.GlobalVariables = append(game.GlobalVariables, 0)
+	}
+	game.GlobalVariables[index] = value
+	game.NumGlobalVariables = Int32(len(game.GlobalVariables))
+	return nil
+}
+
+// NamedGlobalVars returns the global variables named by the module, keyed by
+// their names.
+func (game Game) NamedGlobalVars(options LoadOptions) map[string]Int64 {
+	vars := make(map[string]Int64)
+	if options.Module == nil {
+		return vars
+	}
+	for index, name := range options.Module.Variables {
+		if index < len(game.GlobalVariables) {
+			vars[name] = game.GlobalVariables[index]
+		}
+	}
+	return vars
+}
@@ -0,0 +1,45 @@
+package savegame
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestGlobalVars(t *testing.T) {
+	variables, err := module.ParseVariables(strings.NewReader("player_honor\r\ng_player_luck\r\nplayer_has_homage\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := LoadOptions{Module: &module.Module{Variables: variables}}
+
+	game := newTestGame(MaxGameVersion, 1, 1)
+	game.GlobalVariables = []Int64{12, -3}
+	game.NumGlobalVariables = 2
+	if honor, err := game.GlobalVar(options, "$player_honor"); err != nil || honor != 12 {
+		t.Errorf("expected player_honor 12, got %d (%v)", honor, err)
+	}
+	if luck, err := game.GlobalVar(options, "g_player_luck"); err != nil || luck != -3 {
+		t.Errorf("expected g_player_luck -3, got %d (%v)", luck, err)
+	}
+	if err := game.SetGlobalVar(options, "player_has_homage", 1); err != nil {
+		t.Fatal(err)
+	}
+	if game.NumGlobalVariables != 3 || game.GlobalVariables[2] != 1 {
+		t.Errorf("expected player_has_homage to be added, got %v", game.GlobalVariables)
+	}
+	if _, err := game.GlobalVar(options, "player_unknown"); err == nil {
+		t.Error("expected an error for a variable the module does not have")
+	}
+	if err := game.SetGlobalVar(options, "player_unknown", 1); err == nil {
+		t.Error("expected an error setting a variable the module does not have")
+	}
+	if _, err := game.GlobalVar(LoadOptions{}, "player_honor"); err == nil {
+		t.Error("expected an error without the module's variables.txt")
+	}
+	named := game.NamedGlobalVars(options)
+	if len(named) != 3 || named["g_player_luck"] != -3 {
+		t.Errorf("expected 3 named global variables, got %v", named)
+	}
+}
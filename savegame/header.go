@@ -0,0 +1,66 @@
+package savegame
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// Summary is the part of a savegame shown by a savegame browser: the header
+// and the in-game date.
+type Summary struct {
+	Header Header
+	Hour   Int32
+	Day    Int32
+	Week   Int32
+	Month  Int32
+	Year   Int32
+}
+
+/* Enough for the header and date of all but the most unusual savegames. */
+const summaryChunkSize = 4096
+
+// DecodeSummary reads only as much of r as is needed for the Summary.
+func DecodeSummary(r io.Reader) (summary Summary, err error) {
+	buf := make([]byte, 0, summaryChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		var game Game
+		err = decodeWith(buf, LoadOptions{}, game.readCalendar)
+		if err == nil {
+			summary.Header = game.Header
+			summary.Hour = game.Hour
+			summary.Day = game.Day
+			summary.Week = game.Week
+			summary.Month = game.Month
+			summary.Year = game.Year
+			return summary, nil
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF || !errors.Is(err, io.ErrUnexpectedEOF) {
+			return summary, err
+		}
+		if readErr != nil {
+			return summary, readErr
+		}
+		buf = append(buf, make([]byte, cap(buf))...)[:len(buf)]
+	}
+}
+
+// ReadSummary reads the header and in-game date of the savegame at path
+// without loading the rest of it.
+func ReadSummary(path string) (summary Summary, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return summary, err
+	}
+	defer file.Close()
+	return DecodeSummary(file)
+}
+
+// ReadHeader reads the header of the savegame at path without loading the
+// rest of it.
+func ReadHeader(path string) (header Header, err error) {
+	summary, err := ReadSummary(path)
+	return summary.Header, err
+}
@@ -0,0 +1,37 @@
+package savegame
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSummary(t *testing.T) {
+	for _, playerName := range []string{"Player", strings.Repeat("Long Name ", 1000)} {
+		game := newTestGame(1162, 200, 500)
+		game.Header.PlayerName = testString(playerName)
+		r := bytes.NewReader(game.write(LoadOptions{}))
+		summary, err := DecodeSummary(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(summary.Header, game.Header) {
+			t.Errorf("expected header %+v, got %+v", game.Header, summary.Header)
+		}
+		if summary.Day != game.Day || summary.Hour != game.Hour || summary.Year != game.Year {
+			t.Errorf("expected day %d hour %d year %d, got day %d hour %d year %d",
+				game.Day, game.Hour, game.Year, summary.Day, summary.Hour, summary.Year)
+		}
+		if r.Len() == 0 {
+			t.Error("expected the summary to be decoded without reading the whole savegame")
+		}
+	}
+}
+
+func TestDecodeSummaryTruncated(t *testing.T) {
+	game := newTestGame(1162, 1, 1)
+	if _, err := DecodeSummary(bytes.NewReader(game.write(LoadOptions{})[:40])); err == nil {
+		t.Error("expected an error for a truncated savegame")
+	}
+}
@@ -0,0 +1,39 @@
+package savegame
+
+import (
+	"fmt"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+// Item.ItemFlags is taken to hold the item's modifier in its top byte and,
+// for items that are used up such as ammunition and food, the amount left in
+// its low 16 bits.
+const (
+	itemModifierShift = 24
+	itemModifierMask  = 0xff << itemModifierShift
+	itemAmountMask    = 0xffff
+)
+
+func (item Item) Modifier() module.ItemModifier {
+	return module.ItemModifier(uint32(item.ItemFlags) >> itemModifierShift)
+}
+
+// SetModifier sets the item's modifier. If the module has an item_kinds1.txt,
+// the modifier must be one the item's kind can have.
+func (item *Item) SetModifier(options LoadOptions, modifier module.ItemModifier) error {
+	if modifier < 0 || modifier >= module.NumItemModifiers {
+		return fmt.Errorf("savegame: invalid item modifier %d", int(modifier))
+	}
+	if options.Module != nil {
+		if kind, ok := options.Module.ItemKind(int(item.ItemKindId)); ok && !kind.HasModifier(modifier) {
+			return fmt.Errorf("savegame: %s cannot be %s", kind.Name, modifier)
+		}
+	}
+	item.ItemFlags = Int32(uint32(item.ItemFlags)&^itemModifierMask | uint32(modifier)<<itemModifierShift)
+	return nil
+}
+
+func (item Item) Amount() int {
+	return int(item.ItemFlags & itemAmountMask)
+}
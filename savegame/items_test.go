@@ -0,0 +1,31 @@
+package savegame
+
+import (
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestItemModifier(t *testing.T) {
+	item := Item{ItemKindId: 1, ItemFlags: 0x0d00001e}
+	if modifier := item.Modifier(); modifier != module.ImodBalanced {
+		t.Errorf("expected balanced, got %s", modifier)
+	}
+	if amount := item.Amount(); amount != 30 {
+		t.Errorf("expected amount 30, got %d", amount)
+	}
+	if err := item.SetModifier(LoadOptions{}, module.ImodLordly); err != nil {
+		t.Fatal(err)
+	}
+	if item.ItemFlags != 0x1d00001e {
+		t.Errorf("expected only the modifier to change, got 0x%08x", item.ItemFlags)
+	}
+
+	options := LoadOptions{Module: &module.Module{ItemKinds: []module.ItemKind{{Id: 0}, {Id: 1, Name: "Heavy Lance", ModifierBits: 1 << module.ImodBalanced}}}}
+	if err := item.SetModifier(options, module.ImodRusty); err == nil {
+		t.Error("expected an error for a modifier the item kind cannot have")
+	}
+	if err := item.SetModifier(options, module.ImodBalanced); err != nil {
+		t.Error(err)
+	}
+}
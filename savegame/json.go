@@ -0,0 +1,94 @@
+package savegame
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/* Strings are written as JSON strings so that exported savegames can be edited
+by hand. The few that are not valid UTF-8 are written as {"Chars": base64}. */
+
+func (s String) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(s.Chars) {
+		return json.Marshal(string(s.Chars))
+	}
+	return json.Marshal(struct{ Chars []byte }{s.Chars})
+}
+
+// UnmarshalJSON accepts a JSON string or an object with base64 Chars, which
+// includes the {"NumChars", "Chars"} objects of older exports. NumChars is
+// always recomputed.
+func (s *String) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		s.Chars = []byte(text)
+		s.syncCounts()
+		return nil
+	}
+	var object struct{ Chars []byte }
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	s.Chars = object.Chars
+	if s.Chars == nil {
+		s.Chars = []byte{}
+	}
+	s.syncCounts()
+	return nil
+}
+
+/* The bits of the NaN written by the game. Other NaNs are written as hex bits. */
+const canonicalNaN = 0x7fc00000
+
+// MarshalJSON writes finite floats as the shortest number that reads back as
+// the same float32. As JSON has no NaN or infinities, those are written as the
+// strings "NaN", "+Inf" and "-Inf", or as "0x7fc00001"-style bits for NaNs
+// other than the usual one, so that no value is lost.
+func (f Float) MarshalJSON() ([]byte, error) {
+	f64 := float64(f)
+	switch {
+	case math.IsInf(f64, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(f64, -1):
+		return []byte(`"-Inf"`), nil
+	case math.IsNaN(f64):
+		bits := math.Float32bits(float32(f))
+		if bits == canonicalNaN {
+			return []byte(`"NaN"`), nil
+		}
+		return []byte(fmt.Sprintf(`"0x%08x"`, bits)), nil
+	}
+	return strconv.AppendFloat(nil, f64, 'g', -1, 32), nil
+}
+
+func (f *Float) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var number float32
+		if err := json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		*f = Float(number)
+		return nil
+	}
+	switch text {
+	case "+Inf", "Inf":
+		*f = Float(math.Inf(1))
+	case "-Inf":
+		*f = Float(math.Inf(-1))
+	case "NaN":
+		*f = Float(math.Float32frombits(canonicalNaN))
+	default:
+		hex, ok := strings.CutPrefix(text, "0x")
+		bits, err := strconv.ParseUint(hex, 16, 32)
+		if !ok || err != nil {
+			return fmt.Errorf("savegame: invalid float %q", text)
+		}
+		*f = Float(math.Float32frombits(uint32(bits)))
+	}
+	return nil
+}
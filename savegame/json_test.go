@@ -0,0 +1,93 @@
+package savegame
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestJsonRoundTrip(t *testing.T) {
+	game := newTestGame(1162, 10, 200)
+	game.Header.PlayerName = testString("Ælfgifu <\"the\" fair>")
+	game.Troops[0].Name = String{NumChars: 3, Chars: []byte{0xff, 'a', 0xfe}}
+	game.GlobalHazeAmount = Float(math.NaN())
+	game.PartyRecords[1].Party.Morale = Float(math.Float32frombits(0x7fc01234))
+	game.PartyRecords[2].Party.Hunger = Float(math.Inf(-1))
+
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var imported Game
+	if err := json.Unmarshal(data, &imported); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(imported.write(LoadOptions{}), game.write(LoadOptions{})) {
+		t.Error("savegame was different after exporting to and importing from JSON")
+	}
+}
+
+func TestStringUnmarshalJson(t *testing.T) {
+	for _, data := range []string{`"sg00"`, `{"NumChars": 99, "Chars": "c2cwMA=="}`, `{"Chars": "c2cwMA=="}`} {
+		var s String
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			t.Fatal(err)
+		}
+		if s.String() != "sg00" || s.NumChars != 4 {
+			t.Errorf("expected sg00 with 4 chars from %s, got %q with %d chars", data, s, s.NumChars)
+		}
+	}
+}
+
+func TestSyncCounts(t *testing.T) {
+	game := newTestGame(1162, 10, 20)
+	party := &game.PartyRecords[1].Party
+	party.Stacks = party.Stacks[:1]
+	party.Name.Chars = []byte("Longer Party Name")
+	game.Troops = append(game.Troops, game.Troops[1])
+	game.SyncCounts()
+	if party.NumStacks != 1 || party.Name.NumChars != 17 || game.NumTroops != 21 {
+		t.Errorf("expected counts 1, 17 and 21, got %d, %d and %d", party.NumStacks, party.Name.NumChars, game.NumTroops)
+	}
+
+	synced := newTestGame(1162, 10, 20)
+	synced.SyncCounts()
+	if !reflect.DeepEqual(synced, newTestGame(1162, 10, 20)) {
+		t.Error("syncing the counts of a consistent game changed it")
+	}
+}
+
+func TestFloatJsonIsLossless(t *testing.T) {
+	tests := []struct {
+		bits uint32
+		json string
+	}{
+		{0x3dcccccd, `0.1`},
+		{0x80000000, `-0`},
+		{0x00000001, `1e-45`},
+		{0x7f7fffff, `3.4028235e+38`},
+		{0x7fc00000, `"NaN"`},
+		{0xffc00001, `"0xffc00001"`},
+		{0x7f800000, `"+Inf"`},
+		{0xff800000, `"-Inf"`},
+	}
+	for _, test := range tests {
+		f := Float(math.Float32frombits(test.bits))
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.json {
+			t.Errorf("expected 0x%08x to marshal to %s, got %s", test.bits, test.json, data)
+		}
+		var unmarshaled Float
+		if err := json.Unmarshal(data, &unmarshaled); err != nil {
+			t.Fatal(err)
+		}
+		if bits := math.Float32bits(float32(unmarshaled)); bits != test.bits {
+			t.Errorf("expected %s to unmarshal to 0x%08x, got 0x%08x", data, test.bits, bits)
+		}
+	}
+}
@@ -1,484 +1,599 @@
 package savegame
 
 import (
-	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"os"
 )
 
-func (b *Bool) read(file *os.File) {
-	err := binary.Read(file, binary.LittleEndian, b)
-	if err != nil {
-		panic(err)
-	}
+/* The smallest encoded size of list elements, used to bound untrusted counts. */
+const (
+	minInt32Size         = 4
+	minInt64Size         = 8
+	minNotesSize         = 16 * 13
+	minTriggerSize       = 4 + 3*8
+	minQuestSize         = 4*4 + 3*4 + minNotesSize + 4
+	minSlotListSize      = 4
+	minFactionSize       = 4 + 4 + 1 + 4 + 4 + minNotesSize
+	minMapTrackSize      = 6 * 4
+	minPartyTemplateSize = 4 * 4
+	minPartyStackSize    = 4 * 4
+	minRecordSize        = 4
+	minTroopSize         = 4 + 4*4 + 7*4 + 6*4 + minNotesSize + 8 + 6*4
+)
+
+func (b *Bool) read(d *decoder) {
+	*b = Bool(d.bool())
 }
 
-func (i *Int32) read(file *os.File) {
-	err := binary.Read(file, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
+func (i *Int32) read(d *decoder) {
+	*i = Int32(d.uint32())
 }
 
-func (i *Int64) read(file *os.File) {
-	err := binary.Read(file, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
+func (i *Int64) read(d *decoder) {
+	*i = Int64(d.uint64())
 }
 
-func (i *UInt32) read(file *os.File) {
-	err := binary.Read(file, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
+func (i *UInt32) read(d *decoder) {
+	*i = UInt32(d.uint32())
 }
 
-func (i *UInt64) read(file *os.File) {
-	err := binary.Read(file, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
+func (i *UInt64) read(d *decoder) {
+	*i = UInt64(d.uint64())
 }
 
-func (f *Float) read(file *os.File) {
-	err := binary.Read(file, binary.LittleEndian, f)
-	if err != nil {
-		panic(err)
-	}
-	if DisableNaN && math.IsNaN(float64(*f)) {
-		*f = 0
-	}
+func (f *Float) read(d *decoder) {
+	*f = Float(math.Float32frombits(d.uint32()))
 }
 
-func (s *String) read(file *os.File) {
-	s.NumChars.read(file)
-	s.Chars = make([]byte, s.NumChars)
-	binary.Read(file, binary.LittleEndian, s.Chars)
+func (s *String) read(d *decoder) {
+	s.NumChars.read(d)
+	s.Chars = d.bytes(d.count(s.NumChars, 1))
 }
 
-func (header *Header) read(file *os.File) {
-	header.MagicNumber.read(file)
-	if header.MagicNumber != 0x52445257 {
-		panic("Magic number not 0x52445257")
+func (header *Header) read(d *decoder) {
+	header.MagicNumber.read(d)
+	if header.MagicNumber != magicNumber {
+		d.fail(fmt.Errorf("magic number 0x%08x: %w", uint32(header.MagicNumber), ErrNotWarband))
 	}
-	header.GameVersion.read(file)
-	header.ModuleVersion.read(file)
-	header.SavegameName.read(file)
-	header.PlayerName.read(file)
-	header.PlayerLevel.read(file)
-	header.Date.read(file)
+	header.GameVersion.read(d)
+	capabilities, err := VersionCapabilities(header.GameVersion)
+	if err != nil {
+		d.fail(err)
+	}
+	d.capabilities = capabilities
+	header.ModuleVersion.read(d)
+	header.SavegameName.read(d)
+	header.PlayerName.read(d)
+	header.PlayerLevel.read(d)
+	header.Date.read(d)
 }
 
-func (trigger *Trigger) read(file *os.File) {
-	trigger.Status.read(file)
-	trigger.CheckTimer.read(file)
-	trigger.DelayTimer.read(file)
-	trigger.RearmTimer.read(file)
+func (trigger *Trigger) read(d *decoder) {
+	trigger.Status.read(d)
+	trigger.CheckTimer.read(d)
+	trigger.DelayTimer.read(d)
+	trigger.RearmTimer.read(d)
 }
 
-func (simpleTrigger *SimpleTrigger) read(file *os.File) {
-	simpleTrigger.CheckTimer.read(file)
+func (simpleTrigger *SimpleTrigger) read(d *decoder) {
+	simpleTrigger.CheckTimer.read(d)
 }
 
-func (note *Note) read(file *os.File) {
-	note.Text.read(file)
-	note.Value.read(file)
-	note.TableauMaterialId.read(file)
-	note.Available.read(file)
+func (note *Note) read(d *decoder) {
+	note.Text.read(d)
+	note.Value.read(d)
+	note.TableauMaterialId.read(d)
+	note.Available.read(d)
 }
 
-func (quest *Quest) read(file *os.File) {
-	quest.Progression.read(file)
-	quest.GiverTroopId.read(file)
-	quest.Number.read(file)
-	quest.StartDate.read(file)
-	quest.Title.read(file)
-	quest.Text.read(file)
-	quest.Giver.read(file)
+func (quest *Quest) read(d *decoder) {
+	quest.Progression.read(d)
+	quest.GiverTroopId.read(d)
+	quest.Number.read(d)
+	quest.StartDate.read(d)
+	quest.Title.read(d)
+	quest.Text.read(d)
+	quest.Giver.read(d)
 	for i := 0; i < len(quest.Notes); i++ {
-		quest.Notes[i].read(file)
+		d.enter("Notes", i)
+		quest.Notes[i].read(d)
+		d.leave()
 	}
-	quest.NumSlots.read(file)
-	quest.Slots = make([]Int64, quest.NumSlots)
+	quest.NumSlots.read(d)
+	quest.Slots = make([]Int64, d.count(quest.NumSlots, minInt64Size))
 	for i := 0; i < len(quest.Slots); i++ {
-		quest.Slots[i].read(file)
+		d.enter("Slots", i)
+		quest.Slots[i].read(d)
+		d.leave()
 	}
 }
 
-func (infoPage *InfoPage) read(file *os.File) {
+func (infoPage *InfoPage) read(d *decoder) {
 	for i := 0; i < len(infoPage.Notes); i++ {
-		infoPage.Notes[i].read(file)
+		d.enter("Notes", i)
+		infoPage.Notes[i].read(d)
+		d.leave()
 	}
 }
 
-func (site *Site) read(file *os.File) {
-	site.NumSlots.read(file)
-	site.Slots = make([]Int64, site.NumSlots)
+func (site *Site) read(d *decoder) {
+	site.NumSlots.read(d)
+	site.Slots = make([]Int64, d.count(site.NumSlots, minInt64Size))
 	for i := 0; i < len(site.Slots); i++ {
-		site.Slots[i].read(file)
+		d.enter("Slots", i)
+		site.Slots[i].read(d)
+		d.leave()
 	}
 }
 
-func (faction *Faction) read(file *os.File) {
-	faction.NumSlots.read(file)
-	faction.Slots = make([]Int64, faction.NumSlots)
+func (faction *Faction) read(d *decoder) {
+	faction.NumSlots.read(d)
+	faction.Slots = make([]Int64, d.count(faction.NumSlots, minInt64Size))
 	for i := 0; i < len(faction.Slots); i++ {
-		faction.Slots[i].read(file)
+		d.enter("Slots", i)
+		faction.Slots[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(faction.Relations); i++ {
-		faction.Relations[i].read(file)
-	}
-	faction.Name.read(file)
-	faction.Renamed.read(file)
-	faction.Color.read(file)
-	faction.Unused.read(file)
+		d.enter("Relations", i)
+		faction.Relations[i].read(d)
+		d.leave()
+	}
+	faction.Name.read(d)
+	faction.Renamed.read(d)
+	faction.Color.read(d)
+	faction.Unused.read(d)
 	for i := 0; i < len(faction.Notes); i++ {
-		faction.Notes[i].read(file)
+		d.enter("Notes", i)
+		faction.Notes[i].read(d)
+		d.leave()
 	}
 }
 
-func (mapTrack *MapTrack) read(file *os.File) {
-	mapTrack.PositionX.read(file)
-	mapTrack.PositionY.read(file)
-	mapTrack.PositionZ.read(file)
-	mapTrack.Rotation.read(file)
-	mapTrack.Age.read(file)
-	mapTrack.Flags.read(file)
+func (mapTrack *MapTrack) read(d *decoder) {
+	mapTrack.PositionX.read(d)
+	mapTrack.PositionY.read(d)
+	mapTrack.PositionZ.read(d)
+	mapTrack.Rotation.read(d)
+	mapTrack.Age.read(d)
+	mapTrack.Flags.read(d)
 }
 
-func (partyTemplate *PartyTemplate) read(file *os.File) {
-	partyTemplate.NumPartiesCreated.read(file)
-	partyTemplate.NumPartiesDestroyed.read(file)
-	partyTemplate.NumPartiesDestroyedByPlayer.read(file)
-	partyTemplate.NumSlots.read(file)
-	partyTemplate.Slots = make([]Int64, partyTemplate.NumSlots)
+func (partyTemplate *PartyTemplate) read(d *decoder) {
+	partyTemplate.NumPartiesCreated.read(d)
+	partyTemplate.NumPartiesDestroyed.read(d)
+	partyTemplate.NumPartiesDestroyedByPlayer.read(d)
+	partyTemplate.NumSlots.read(d)
+	partyTemplate.Slots = make([]Int64, d.count(partyTemplate.NumSlots, minInt64Size))
 	for i := 0; i < len(partyTemplate.Slots); i++ {
-		partyTemplate.Slots[i].read(file)
-	}
-}
-
-func (partyStack *PartyStack) read(file *os.File) {
-	partyStack.TroopId.read(file)
-	partyStack.NumTroops.read(file)
-	partyStack.NumWoundedTroops.read(file)
-	partyStack.Flags.read(file)
-}
-
-func (party *Party) read(file *os.File, gameVersion Int32) {
-	party.Id.read(file)
-	party.Name.read(file)
-	party.Flags.read(file)
-	party.MenuId.read(file)
-	party.PartyTemplateId.read(file)
-	party.FactionId.read(file)
-	party.Personality.read(file)
-	party.DefaultBehavior.read(file)
-	party.CurrentBehavior.read(file)
-	party.DefaultBehaviorObjectId.read(file)
-	party.CurrentBehaviorObjectId.read(file)
-	party.InitialPositionX.read(file)
-	party.InitialPositionY.read(file)
-	party.TargetPositionX.read(file)
-	party.TargetPositionY.read(file)
-	party.PositionX.read(file)
-	party.PositionY.read(file)
-	party.PositionZ.read(file)
-	party.NumStacks.read(file)
-	party.Stacks = make([]PartyStack, party.NumStacks)
+		d.enter("Slots", i)
+		partyTemplate.Slots[i].read(d)
+		d.leave()
+	}
+}
+
+func (partyStack *PartyStack) read(d *decoder) {
+	partyStack.TroopId.read(d)
+	partyStack.NumTroops.read(d)
+	partyStack.NumWoundedTroops.read(d)
+	partyStack.Flags.read(d)
+}
+
+func (party *Party) read(d *decoder) {
+	party.Id.read(d)
+	party.Name.read(d)
+	party.Flags.read(d)
+	party.MenuId.read(d)
+	party.PartyTemplateId.read(d)
+	party.FactionId.read(d)
+	party.Personality.read(d)
+	party.DefaultBehavior.read(d)
+	party.CurrentBehavior.read(d)
+	party.DefaultBehaviorObjectId.read(d)
+	party.CurrentBehaviorObjectId.read(d)
+	party.InitialPositionX.read(d)
+	party.InitialPositionY.read(d)
+	party.TargetPositionX.read(d)
+	party.TargetPositionY.read(d)
+	party.PositionX.read(d)
+	party.PositionY.read(d)
+	party.PositionZ.read(d)
+	party.NumStacks.read(d)
+	party.Stacks = make([]PartyStack, d.count(party.NumStacks, minPartyStackSize))
 	for i := 0; i < len(party.Stacks); i++ {
-		party.Stacks[i].read(file)
-	}
-	party.Bearing.read(file)
-	party.Renamed.read(file)
-	party.ExtraText.read(file)
-	party.Morale.read(file)
-	party.Hunger.read(file)
-	party.Unused1.read(file)
-	party.PatrolRadius.read(file)
-	party.Initiative.read(file)
-	party.Helpfulness.read(file)
-	party.LabelVisible.read(file)
-	party.BanditAttraction.read(file)
-	if (gameVersion >= 900 && gameVersion < 1000) || gameVersion >= 1020 {
-		party.Marshall.read(file)
-	}
-	party.IgnorePlayerTimer.read(file)
-	party.BannerMapIconId.read(file)
-	if gameVersion >= 1137 {
-		party.ExtraMapIconId.read(file)
-		party.ExtraMapIconUpDownDistance.read(file)
-		party.ExtraMapIconUpDownFrequency.read(file)
-		party.ExtraMapIconRotateFrequency.read(file)
-		party.ExtraMapIconFadeFrequency.read(file)
-	}
-	party.AttachedToPartyId.read(file)
-	if gameVersion >= 1162 {
-		party.Unused2.read(file)
-	}
-	party.IsAttached.read(file)
-	party.NumAttachedPartyIds.read(file)
-	party.AttachedPartyIds = make([]Int32, party.NumAttachedPartyIds)
+		d.enter("Stacks", i)
+		party.Stacks[i].read(d)
+		d.leave()
+	}
+	party.Bearing.read(d)
+	party.Renamed.read(d)
+	party.ExtraText.read(d)
+	party.Morale.read(d)
+	party.Hunger.read(d)
+	party.Unused1.read(d)
+	party.PatrolRadius.read(d)
+	party.Initiative.read(d)
+	party.Helpfulness.read(d)
+	party.LabelVisible.read(d)
+	party.BanditAttraction.read(d)
+	if d.capabilities.HasMarshall {
+		party.Marshall.read(d)
+	}
+	party.IgnorePlayerTimer.read(d)
+	party.BannerMapIconId.read(d)
+	if d.capabilities.HasExtraMapIcon {
+		party.ExtraMapIconId.read(d)
+		party.ExtraMapIconUpDownDistance.read(d)
+		party.ExtraMapIconUpDownFrequency.read(d)
+		party.ExtraMapIconRotateFrequency.read(d)
+		party.ExtraMapIconFadeFrequency.read(d)
+	}
+	party.AttachedToPartyId.read(d)
+	if d.capabilities.HasPartyUnused2 {
+		party.Unused2.read(d)
+	}
+	party.IsAttached.read(d)
+	party.NumAttachedPartyIds.read(d)
+	party.AttachedPartyIds = make([]Int32, d.count(party.NumAttachedPartyIds, minInt32Size))
 	for i := 0; i < len(party.AttachedPartyIds); i++ {
-		party.AttachedPartyIds[i].read(file)
+		d.enter("AttachedPartyIds", i)
+		party.AttachedPartyIds[i].read(d)
+		d.leave()
 	}
-	party.NumParticleSystemIds.read(file)
-	party.ParticleSystemIds = make([]Int32, party.NumParticleSystemIds)
+	party.NumParticleSystemIds.read(d)
+	party.ParticleSystemIds = make([]Int32, d.count(party.NumParticleSystemIds, minInt32Size))
 	for i := 0; i < len(party.ParticleSystemIds); i++ {
-		party.ParticleSystemIds[i].read(file)
+		d.enter("ParticleSystemIds", i)
+		party.ParticleSystemIds[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(party.Notes); i++ {
-		party.Notes[i].read(file)
+		d.enter("Notes", i)
+		party.Notes[i].read(d)
+		d.leave()
 	}
-	party.NumSlots.read(file)
-	party.Slots = make([]Int64, party.NumSlots)
+	party.NumSlots.read(d)
+	party.Slots = make([]Int64, d.count(party.NumSlots, minInt64Size))
 	for i := 0; i < len(party.Slots); i++ {
-		party.Slots[i].read(file)
+		d.enter("Slots", i)
+		party.Slots[i].read(d)
+		d.leave()
 	}
 }
 
-func (partyRecord *PartyRecord) read(file *os.File, gameVersion Int32) {
-	partyRecord.Valid.read(file)
+func (partyRecord *PartyRecord) read(d *decoder) {
+	partyRecord.Valid.read(d)
 	if partyRecord.Valid == 1 {
-		partyRecord.RawId.read(file)
-		partyRecord.Id.read(file)
-		partyRecord.Party.read(file, gameVersion)
+		partyRecord.RawId.read(d)
+		partyRecord.Id.read(d)
+		d.enter("Party", -1)
+		partyRecord.Party.read(d)
+		d.leave()
 	}
 }
 
-func (playerPartyStack *PlayerPartyStack) read(file *os.File, stackIndex int) {
-	playerPartyStack.Experience.read(file)
-	playerPartyStack.NumUpgradeable.read(file)
+func (playerPartyStack *PlayerPartyStack) read(d *decoder, stackIndex int) {
+	playerPartyStack.Experience.read(d)
+	playerPartyStack.NumUpgradeable.read(d)
 	if stackIndex < 32 {
 		for i := 0; i < len(playerPartyStack.TroopDnas); i++ {
-			playerPartyStack.TroopDnas[i].read(file)
+			d.enter("TroopDnas", i)
+			playerPartyStack.TroopDnas[i].read(d)
+			d.leave()
 		}
 	}
 }
 
-func (mapEvent *MapEvent) read(file *os.File) {
-	mapEvent.Unused0.read(file)
-	mapEvent.Type.read(file)
-	mapEvent.PositionX.read(file)
-	mapEvent.PositionY.read(file)
-	mapEvent.LandPositionX.read(file)
-	mapEvent.LandPositionY.read(file)
-	mapEvent.Unused1.read(file)
-	mapEvent.Unused2.read(file)
-	mapEvent.AttackerPartyId.read(file)
-	mapEvent.DefenderPartyId.read(file)
-	mapEvent.BattleSimulationTimer.read(file)
-	mapEvent.NextBattleSimulation.read(file)
+func (mapEvent *MapEvent) read(d *decoder) {
+	mapEvent.Unused0.read(d)
+	mapEvent.Type.read(d)
+	mapEvent.PositionX.read(d)
+	mapEvent.PositionY.read(d)
+	mapEvent.LandPositionX.read(d)
+	mapEvent.LandPositionY.read(d)
+	mapEvent.Unused1.read(d)
+	mapEvent.Unused2.read(d)
+	mapEvent.AttackerPartyId.read(d)
+	mapEvent.DefenderPartyId.read(d)
+	mapEvent.BattleSimulationTimer.read(d)
+	mapEvent.NextBattleSimulation.read(d)
 }
 
-func (mapEventRecord *MapEventRecord) read(file *os.File) {
-	mapEventRecord.Valid.read(file)
+func (mapEventRecord *MapEventRecord) read(d *decoder) {
+	mapEventRecord.Valid.read(d)
 	if mapEventRecord.Valid == 1 {
-		mapEventRecord.Id.read(file)
-		mapEventRecord.MapEvent.read(file)
+		mapEventRecord.Id.read(d)
+		d.enter("MapEvent", -1)
+		mapEventRecord.MapEvent.read(d)
+		d.leave()
 	}
 }
 
-func (item *Item) read(file *os.File) {
-	item.ItemKindId.read(file)
-	item.ItemFlags.read(file)
+func (item *Item) read(d *decoder) {
+	item.ItemKindId.read(d)
+	item.ItemFlags.read(d)
 }
 
-func (troop *Troop) read(file *os.File) {
-	troop.NumSlots.read(file)
-	troop.Slots = make([]Int64, troop.NumSlots)
+func (troop *Troop) read(d *decoder) {
+	troop.NumSlots.read(d)
+	troop.Slots = make([]Int64, d.count(troop.NumSlots, minInt64Size))
 	for i := 0; i < len(troop.Slots); i++ {
-		troop.Slots[i].read(file)
+		d.enter("Slots", i)
+		troop.Slots[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(troop.Attributes); i++ {
-		troop.Attributes[i].read(file)
+		d.enter("Attributes", i)
+		troop.Attributes[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(troop.Proficiencies); i++ {
-		troop.Proficiencies[i].read(file)
+		d.enter("Proficiencies", i)
+		troop.Proficiencies[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(troop.Skills); i++ {
-		troop.Skills[i].read(file)
+		d.enter("Skills", i)
+		troop.Skills[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(troop.Notes); i++ {
-		troop.Notes[i].read(file)
-	}
-	troop.Flags.read(file)
-	troop.SiteIdAndEntryNo.read(file)
-	troop.SkillPoints.read(file)
-	troop.AttributePoints.read(file)
-	troop.ProficiencyPoints.read(file)
-	troop.Level.read(file)
+		d.enter("Notes", i)
+		troop.Notes[i].read(d)
+		d.leave()
+	}
+	troop.Flags.read(d)
+	troop.SiteIdAndEntryNo.read(d)
+	troop.SkillPoints.read(d)
+	troop.AttributePoints.read(d)
+	troop.ProficiencyPoints.read(d)
+	troop.Level.read(d)
 	isHero := troop.Flags&heroFlag != 0
-	if isHero || loadRegularTroopInventory {
-		troop.Gold.read(file)
-		troop.Experience.read(file)
-		troop.Health.read(file)
-		troop.FactionId.read(file)
+	if isHero || d.options.regularTroopInventory() {
+		troop.Gold.read(d)
+		troop.Experience.read(d)
+		troop.Health.read(d)
+		troop.FactionId.read(d)
 		for i := 0; i < len(troop.InventoryItems); i++ {
-			troop.InventoryItems[i].read(file)
+			d.enter("InventoryItems", i)
+			troop.InventoryItems[i].read(d)
+			d.leave()
 		}
 		for i := 0; i < len(troop.EquippedItems); i++ {
-			troop.EquippedItems[i].read(file)
+			d.enter("EquippedItems", i)
+			troop.EquippedItems[i].read(d)
+			d.leave()
 		}
 		for i := 0; i < len(troop.FaceKeys); i++ {
-			troop.FaceKeys[i].read(file)
+			d.enter("FaceKeys", i)
+			troop.FaceKeys[i].read(d)
+			d.leave()
 		}
-		troop.Renamed.read(file)
+		troop.Renamed.read(d)
 		if troop.Renamed {
-			troop.Name.read(file)
-			troop.NamePlural.read(file)
+			troop.Name.read(d)
+			troop.NamePlural.read(d)
 		}
 	}
-	troop.ClassNo.read(file)
+	troop.ClassNo.read(d)
 }
 
-func (itemKind *ItemKind) read(file *os.File) {
-	itemKind.NumSlots.read(file)
-	itemKind.Slots = make([]Int64, itemKind.NumSlots)
+func (itemKind *ItemKind) read(d *decoder) {
+	itemKind.NumSlots.read(d)
+	itemKind.Slots = make([]Int64, d.count(itemKind.NumSlots, minInt64Size))
 	for i := 0; i < len(itemKind.Slots); i++ {
-		itemKind.Slots[i].read(file)
-	}
-}
-
-func (game *Game) read(file *os.File) {
-	game.Header.read(file)
-	game.GameTime.read(file)
-	game.RandomSeed.read(file)
-	game.SaveMode.read(file)
-	if game.Header.GameVersion >= 1137 {
-		game.CombatDifficulty.read(file)
-		game.CombatDifficultyFriendlies.read(file)
-		game.ReduceCombatAi.read(file)
-		game.ReduceCampaignAi.read(file)
-		game.CombatSpeed.read(file)
-	}
-	game.DateTimer.read(file)
-	game.Hour.read(file)
-	game.Day.read(file)
-	game.Week.read(file)
-	game.Month.read(file)
-	game.Year.read(file)
-	game.Unused0.read(file)
-	game.GlobalCloudAmount.read(file)
-	game.GlobalHazeAmount.read(file)
-	game.AverageDifficulty.read(file)
-	game.AverageDifficultyPeriod.read(file)
-	game.Unused1.read(file)
-	game.Unused2.read(file)
-	game.TutorialFlags.read(file)
-	game.DefaultPrisonerPrice.read(file)
-	game.EncounteredParty1Id.read(file)
-	game.EncounteredParty2Id.read(file)
-	game.CurrentMenuId.read(file)
-	game.CurrentSiteId.read(file)
-	game.CurrentEntryNo.read(file)
-	game.CurrentMissionTemplateId.read(file)
-	game.PartyCreationMinRandomValue.read(file)
-	game.PartyCreationMaxRandomValue.read(file)
-	game.GameLog.read(file)
-	for i := 0; i < len(game.Unused3); i++ {
-		game.Unused3[i].read(file)
+		d.enter("Slots", i)
+		itemKind.Slots[i].read(d)
+		d.leave()
 	}
-	game.Unused4.read(file)
-	game.RestPeriod.read(file)
-	game.RestTimeSpeed.read(file)
-	game.RestIsInteractive.read(file)
-	game.RestRemainAttackable.read(file)
+}
+
+/* readCalendar reads everything up to and including the in-game date. */
+func (game *Game) readCalendar(d *decoder) {
+	d.enter("Header", -1)
+	game.Header.read(d)
+	d.leave()
+	game.GameTime.read(d)
+	game.RandomSeed.read(d)
+	game.SaveMode.read(d)
+	if d.capabilities.HasCombatSettings {
+		game.CombatDifficulty.read(d)
+		game.CombatDifficultyFriendlies.read(d)
+		game.ReduceCombatAi.read(d)
+		game.ReduceCampaignAi.read(d)
+		game.CombatSpeed.read(d)
+	}
+	game.DateTimer.read(d)
+	game.Hour.read(d)
+	game.Day.read(d)
+	game.Week.read(d)
+	game.Month.read(d)
+	game.Year.read(d)
+}
+
+func (game *Game) read(d *decoder) {
+	game.readCalendar(d)
+	game.Unused0.read(d)
+	game.GlobalCloudAmount.read(d)
+	game.GlobalHazeAmount.read(d)
+	game.AverageDifficulty.read(d)
+	game.AverageDifficultyPeriod.read(d)
+	game.Unused1.read(d)
+	game.Unused2.read(d)
+	game.TutorialFlags.read(d)
+	game.DefaultPrisonerPrice.read(d)
+	game.EncounteredParty1Id.read(d)
+	game.EncounteredParty2Id.read(d)
+	game.CurrentMenuId.read(d)
+	game.CurrentSiteId.read(d)
+	game.CurrentEntryNo.read(d)
+	game.CurrentMissionTemplateId.read(d)
+	game.PartyCreationMinRandomValue.read(d)
+	game.PartyCreationMaxRandomValue.read(d)
+	game.GameLog.read(d)
+	for i := 0; i < len(game.Unused3); i++ {
+		d.enter("Unused3", i)
+		game.Unused3[i].read(d)
+		d.leave()
+	}
+	game.Unused4.read(d)
+	game.RestPeriod.read(d)
+	game.RestTimeSpeed.read(d)
+	game.RestIsInteractive.read(d)
+	game.RestRemainAttackable.read(d)
 	for i := 0; i < len(game.ClassNames); i++ {
-		game.ClassNames[i].read(file)
+		d.enter("ClassNames", i)
+		game.ClassNames[i].read(d)
+		d.leave()
 	}
-	game.NumGlobalVariables.read(file)
-	game.GlobalVariables = make([]Int64, game.NumGlobalVariables)
+	game.NumGlobalVariables.read(d)
+	game.GlobalVariables = make([]Int64, d.count(game.NumGlobalVariables, minInt64Size))
 	for i := 0; i < len(game.GlobalVariables); i++ {
-		game.GlobalVariables[i].read(file)
+		d.enter("GlobalVariables", i)
+		game.GlobalVariables[i].read(d)
+		d.leave()
 	}
-	game.NumTriggers.read(file)
-	game.Triggers = make([]Trigger, game.NumTriggers)
+	game.NumTriggers.read(d)
+	game.Triggers = make([]Trigger, d.count(game.NumTriggers, minTriggerSize))
 	for i := 0; i < len(game.Triggers); i++ {
-		game.Triggers[i].read(file)
+		d.enter("Triggers", i)
+		game.Triggers[i].read(d)
+		d.leave()
 	}
-	game.NumSimpleTriggers.read(file)
-	game.SimpleTriggers = make([]SimpleTrigger, game.NumSimpleTriggers)
+	game.NumSimpleTriggers.read(d)
+	game.SimpleTriggers = make([]SimpleTrigger, d.count(game.NumSimpleTriggers, minInt64Size))
 	for i := 0; i < len(game.SimpleTriggers); i++ {
-		game.SimpleTriggers[i].read(file)
+		d.enter("SimpleTriggers", i)
+		game.SimpleTriggers[i].read(d)
+		d.leave()
 	}
-	game.NumQuests.read(file)
-	game.Quests = make([]Quest, game.NumQuests)
+	game.NumQuests.read(d)
+	game.Quests = make([]Quest, d.count(game.NumQuests, minQuestSize))
 	for i := 0; i < len(game.Quests); i++ {
-		game.Quests[i].read(file)
+		d.enter("Quests", i)
+		game.Quests[i].read(d)
+		d.leave()
 	}
-	game.NumInfoPages.read(file)
-	game.InfoPages = make([]InfoPage, game.NumInfoPages)
+	game.NumInfoPages.read(d)
+	game.InfoPages = make([]InfoPage, d.count(game.NumInfoPages, minNotesSize))
 	for i := 0; i < len(game.InfoPages); i++ {
-		game.InfoPages[i].read(file)
+		d.enter("InfoPages", i)
+		game.InfoPages[i].read(d)
+		d.leave()
 	}
-	game.NumSites.read(file)
-	game.Sites = make([]Site, game.NumSites)
+	game.NumSites.read(d)
+	game.Sites = make([]Site, d.count(game.NumSites, minSlotListSize))
 	for i := 0; i < len(game.Sites); i++ {
-		game.Sites[i].read(file)
+		d.enter("Sites", i)
+		game.Sites[i].read(d)
+		d.leave()
 	}
-	game.NumFactions.read(file)
-	game.Factions = make([]Faction, game.NumFactions)
+	game.NumFactions.read(d)
+	game.Factions = make([]Faction, d.count(game.NumFactions, minFactionSize))
 	for i := 0; i < len(game.Factions); i++ {
-		game.Factions[i].Relations = make([]Float, game.NumFactions)
-		game.Factions[i].read(file)
+		d.enter("Factions", i)
+		game.Factions[i].Relations = make([]Float, d.count(game.NumFactions, minInt32Size))
+		game.Factions[i].read(d)
+		d.leave()
 	}
-	game.NumMapTracks.read(file)
-	game.MapTracks = make([]MapTrack, game.NumMapTracks)
+	game.NumMapTracks.read(d)
+	game.MapTracks = make([]MapTrack, d.count(game.NumMapTracks, minMapTrackSize))
 	for i := 0; i < len(game.MapTracks); i++ {
-		game.MapTracks[i].read(file)
+		d.enter("MapTracks", i)
+		game.MapTracks[i].read(d)
+		d.leave()
 	}
-	game.NumPartyTemplates.read(file)
-	game.PartyTemplates = make([]PartyTemplate, game.NumPartyTemplates)
+	game.NumPartyTemplates.read(d)
+	game.PartyTemplates = make([]PartyTemplate, d.count(game.NumPartyTemplates, minPartyTemplateSize))
 	for i := 0; i < len(game.PartyTemplates); i++ {
-		game.PartyTemplates[i].read(file)
+		d.enter("PartyTemplates", i)
+		game.PartyTemplates[i].read(d)
+		d.leave()
 	}
-	game.NumPartyRecords.read(file)
-	game.NumPartiesCreated.read(file)
-	game.PartyRecords = make([]PartyRecord, game.NumPartyRecords)
+	game.NumPartyRecords.read(d)
+	game.NumPartiesCreated.read(d)
+	game.PartyRecords = make([]PartyRecord, d.count(game.NumPartyRecords, minRecordSize))
 	for i := 0; i < len(game.PartyRecords); i++ {
-		game.PartyRecords[i].read(file, game.Header.GameVersion)
+		d.enter("PartyRecords", i)
+		game.PartyRecords[i].read(d)
+		d.leave()
+	}
+	if len(game.PartyRecords) == 0 {
+		d.fail(errors.New("missing player party record"))
 	}
 	playerParty := game.PartyRecords[0].Party
 	game.PlayerPartyStackAdditionalInfo = make([]PlayerPartyStack, len(playerParty.Stacks))
 	for i := 0; i < len(playerParty.Stacks); i++ {
-		if hasAdditionalInfo(playerParty, i) {
-			game.PlayerPartyStackAdditionalInfo[i].read(file, i)
+		if hasAdditionalInfo(playerParty, i, d.options) {
+			d.enter("PlayerPartyStackAdditionalInfo", i)
+			game.PlayerPartyStackAdditionalInfo[i].read(d, i)
+			d.leave()
 		}
 	}
-	game.NumMapEventRecords.read(file)
-	game.NumMapEventsCreated.read(file)
-	game.MapEventRecords = make([]MapEventRecord, game.NumMapEventRecords)
+	game.NumMapEventRecords.read(d)
+	game.NumMapEventsCreated.read(d)
+	game.MapEventRecords = make([]MapEventRecord, d.count(game.NumMapEventRecords, minRecordSize))
 	for i := 0; i < len(game.MapEventRecords); i++ {
-		game.MapEventRecords[i].read(file)
+		d.enter("MapEventRecords", i)
+		game.MapEventRecords[i].read(d)
+		d.leave()
 	}
-	game.NumTroops.read(file)
-	game.Troops = make([]Troop, game.NumTroops)
+	game.NumTroops.read(d)
+	game.Troops = make([]Troop, d.count(game.NumTroops, minTroopSize))
 	for i := 0; i < len(game.Troops); i++ {
-		game.Troops[i].read(file)
+		d.enter("Troops", i)
+		game.Troops[i].read(d)
+		d.leave()
 	}
 	for i := 0; i < len(game.Unused5); i++ {
-		game.Unused5[i].read(file)
+		d.enter("Unused5", i)
+		game.Unused5[i].read(d)
+		d.leave()
 	}
-	game.NumItemKinds.read(file)
-	game.ItemKinds = make([]ItemKind, game.NumItemKinds)
+	game.NumItemKinds.read(d)
+	game.ItemKinds = make([]ItemKind, d.count(game.NumItemKinds, minSlotListSize))
 	for i := 0; i < len(game.ItemKinds); i++ {
-		game.ItemKinds[i].read(file)
+		d.enter("ItemKinds", i)
+		game.ItemKinds[i].read(d)
+		d.leave()
+	}
+	game.PlayerFaceKeys0.read(d)
+	game.PlayerFaceKeys1.read(d)
+	game.PlayerKillCount.read(d)
+	game.PlayerWoundedCount.read(d)
+	game.PlayerOwnTroopKillCount.read(d)
+	game.PlayerOwnTroopWoundedCount.read(d)
+}
+
+func decodeWith(data []byte, options LoadOptions, read func(d *decoder)) (err error) {
+	d := &decoder{data: data, options: options}
+	defer d.recover(&err)
+	read(d)
+	return nil
+}
+
+func decode(data []byte, options LoadOptions) (game Game, err error) {
+	err = decodeWith(data, options, game.read)
+	return game, err
+}
+
+// Decode reads a savegame from r. If the data is truncated or corrupt, the
+// returned error is a *DecodeError describing where decoding stopped.
+func Decode(r io.Reader, options LoadOptions) (game Game, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return game, err
 	}
-	game.PlayerFaceKeys0.read(file)
-	game.PlayerFaceKeys1.read(file)
-	game.PlayerKillCount.read(file)
-	game.PlayerWoundedCount.read(file)
-	game.PlayerOwnTroopKillCount.read(file)
-	game.PlayerOwnTroopWoundedCount.read(file)
+	return decode(data, options)
 }
 
-func Load(path string) (game Game, err error) {
-	file, err := os.Open(path)
+func Load(path string, options LoadOptions) (game Game, err error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return game, err
 	}
-	defer file.Close()
-	game.read(file)
-	return game, nil
+	return decode(data, options)
 }
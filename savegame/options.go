@@ -0,0 +1,39 @@
+package savegame
+
+import "github.com/analyticdan/mbw-savegame-editor/module"
+
+// LoadOptions describes the module a savegame was made with, as some modules
+// change its layout. The zero value describes Native.
+type LoadOptions struct {
+	Module *module.Module
+}
+
+// SaveOptions configures Save and Encode. Its LoadOptions must be those the
+// game was loaded with.
+type SaveOptions struct {
+	LoadOptions
+	// SkipValidation saves games that Validate finds issues with, except for
+	// lists whose lengths disagree, which cannot be saved.
+	SkipValidation bool
+	// Backups is the number of earlier versions of a file Save keeps, as
+	// "<path>.bak.1" (the most recent) to "<path>.bak.<Backups>". Zero keeps
+	// none.
+	Backups int
+}
+
+func (options LoadOptions) regularTroopInventory() bool {
+	if options.Module == nil {
+		return false
+	}
+	return options.Module.RegularTroopInventory()
+}
+
+// isHero reports whether troopId is a hero according to the module's
+// troops.txt, or to Native's troop ranges if it is not loaded.
+func (options LoadOptions) isHero(troopId int) bool {
+	if options.Module != nil && len(options.Module.Troops) > 0 {
+		troop, ok := options.Module.Troop(troopId)
+		return ok && troop.IsHero()
+	}
+	return troopId == 0 || (troopId >= 194 && troopId < 463)
+}
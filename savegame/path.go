@@ -0,0 +1,176 @@
+package savegame
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Get returns the model element at path, e.g. "Troops[0].Gold" or
+// "Quests[3].Notes[15].Text". Paths are written like those of Issue and
+// Change: field names separated by dots, each followed by any number of
+// indices into slices and arrays. Elements that are lists or structs are
+// returned as is, sharing their slices with game.
+func Get(game Game, path string) (any, error) {
+	element, err := lookup(reflect.ValueOf(&game).Elem(), path)
+	if err != nil {
+		return nil, err
+	}
+	return element.Interface(), nil
+}
+
+// Set sets the model element at path to value. The value must have the type
+// of the element, or be a Go value of the same kind that fits in it: e.g. an
+// Int32 can be set from any integer between math.MinInt32 and
+// math.MaxInt32, a Float from any number, and a String from a string or
+// []byte, which also sets its NumChars. Lists can be replaced but not grown
+// through their elements; replacing one leaves its Num.* field to
+// Game.SyncCounts.
+func Set(game *Game, path string, value any) error {
+	element, err := lookup(reflect.ValueOf(game).Elem(), path)
+	if err != nil {
+		return err
+	}
+	converted, err := convert(path, element.Type(), reflect.ValueOf(value))
+	if err != nil {
+		return err
+	}
+	element.Set(converted)
+	return nil
+}
+
+// SetText sets the model element at path to text parsed as its type, as for
+// command line arguments. Integers may be written in any base accepted by
+// strconv.ParseInt, such as 0x1f, and Bools as accepted by strconv.ParseBool.
+func SetText(game *Game, path string, text string) error {
+	element, err := lookup(reflect.ValueOf(game).Elem(), path)
+	if err != nil {
+		return err
+	}
+	var value any
+	switch {
+	case element.Type() == stringType:
+		value = text
+	case element.Kind() == reflect.Bool:
+		value, err = strconv.ParseBool(text)
+	case element.CanInt():
+		value, err = strconv.ParseInt(text, 0, element.Type().Bits())
+	case element.CanUint():
+		value, err = strconv.ParseUint(text, 0, element.Type().Bits())
+	case element.CanFloat():
+		value, err = strconv.ParseFloat(text, element.Type().Bits())
+	default:
+		return fmt.Errorf("savegame: %s is a %s and cannot be set from text", path, typeName(element.Type()))
+	}
+	if err != nil {
+		return fmt.Errorf("savegame: %s: %w", path, err)
+	}
+	return Set(game, path, value)
+}
+
+func lookup(element reflect.Value, path string) (reflect.Value, error) {
+	if path == "" {
+		return element, fmt.Errorf("savegame: empty path")
+	}
+	elementPath := "Game"
+	for i, segment := range strings.Split(path, ".") {
+		name, indices, indexed := strings.Cut(segment, "[")
+		if element.Kind() != reflect.Struct || element.Type() == stringType {
+			return element, fmt.Errorf("savegame: %s is a %s and has no field %q", elementPath, typeName(element.Type()), name)
+		}
+		field, ok := element.Type().FieldByName(name)
+		if !ok || !field.IsExported() {
+			return element, fmt.Errorf("savegame: %s has no field %q", elementPath, name)
+		}
+		element = element.FieldByIndex(field.Index)
+		if i == 0 {
+			elementPath = name
+		} else {
+			elementPath += "." + name
+		}
+		for indexed {
+			index, rest, ok := strings.Cut(indices, "]")
+			if !ok || (rest != "" && rest[0] != '[') {
+				return element, fmt.Errorf("savegame: malformed path %q", path)
+			}
+			indices, indexed = strings.CutPrefix(rest, "[")
+			if element.Kind() != reflect.Slice && element.Kind() != reflect.Array {
+				return element, fmt.Errorf("savegame: %s is a %s and cannot be indexed", elementPath, typeName(element.Type()))
+			}
+			n, err := strconv.Atoi(index)
+			if err != nil {
+				return element, fmt.Errorf("savegame: malformed index %q in path %q", index, path)
+			}
+			if n < 0 || n >= element.Len() {
+				return element, fmt.Errorf("savegame: index %d out of range, %s has %d elements", n, elementPath, element.Len())
+			}
+			element = element.Index(n)
+			elementPath = fmt.Sprintf("%s[%d]", elementPath, n)
+		}
+	}
+	return element, nil
+}
+
+func convert(path string, elementType reflect.Type, value reflect.Value) (reflect.Value, error) {
+	if !value.IsValid() {
+		return value, fmt.Errorf("savegame: %s is a %s, not nil", path, typeName(elementType))
+	}
+	mismatch := func() (reflect.Value, error) {
+		return value, fmt.Errorf("savegame: %s is a %s, not a %s", path, typeName(elementType), typeName(value.Type()))
+	}
+	outOfRange := func() (reflect.Value, error) {
+		return value, fmt.Errorf("savegame: %v is out of range for %s, a %s", value.Interface(), path, typeName(elementType))
+	}
+	if elementType == stringType {
+		var s String
+		switch v := value.Interface().(type) {
+		case String:
+			s = v
+		case string:
+			s.Chars = []byte(v)
+		case []byte:
+			s.Chars = v
+		default:
+			return mismatch()
+		}
+		s.NumChars = Int32(len(s.Chars))
+		return reflect.ValueOf(s), nil
+	}
+	if value.Type() == elementType {
+		return value, nil
+	}
+	converted := reflect.New(elementType).Elem()
+	switch {
+	case elementType.Kind() == reflect.Bool && value.Kind() == reflect.Bool:
+	case converted.CanInt() && value.CanInt():
+		if converted.OverflowInt(value.Int()) {
+			return outOfRange()
+		}
+	case converted.CanInt() && value.CanUint():
+		if value.Uint() > 1<<(elementType.Bits()-1)-1 {
+			return outOfRange()
+		}
+	case converted.CanUint() && value.CanInt():
+		if value.Int() < 0 || converted.OverflowUint(uint64(value.Int())) {
+			return outOfRange()
+		}
+	case converted.CanUint() && value.CanUint():
+		if converted.OverflowUint(value.Uint()) {
+			return outOfRange()
+		}
+	case converted.CanFloat() && value.CanFloat():
+		if converted.OverflowFloat(value.Float()) {
+			return outOfRange()
+		}
+	case converted.CanFloat() && (value.CanInt() || value.CanUint()):
+	default:
+		return mismatch()
+	}
+	return value.Convert(elementType), nil
+}
+
+/* Names types as declared in model.go, e.g. "[]Int64" rather than "[]savegame.Int64". */
+func typeName(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "savegame.", "")
+}
@@ -0,0 +1,88 @@
+package savegame
+
+import "testing"
+
+func TestGetSet(t *testing.T) {
+	game := newTestGame(1162, 4, 10)
+	if err := Set(&game, "Troops[0].Gold", 5000); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(&game, "PartyRecords[2].Party.Slots[3]", Int64(-3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(&game, "Quests[0].Notes[15].Text", "A note"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetText(&game, "Troops[0].Proficiencies[2]", "134.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetText(&game, "Troops[1].Flags", "0x10"); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string]any{
+		"Troops[0].Gold":                 UInt32(5000),
+		"PartyRecords[2].Party.Slots[3]": Int64(-3),
+		"Quests[0].Notes[15].Text":       String{NumChars: 6, Chars: []byte("A note")},
+		"Troops[0].Proficiencies[2]":     Float(134.5),
+		"Troops[1].Flags":                UInt64(0x10),
+		"Header.PlayerName":              game.Header.PlayerName,
+	} {
+		value, err := Get(game, path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if s, ok := expected.(String); ok {
+			if value.(String).NumChars != s.NumChars || value.(String).String() != s.String() {
+				t.Errorf("expected %s to be %v, got %v", path, expected, value)
+			}
+		} else if value != expected {
+			t.Errorf("expected %s to be %v (%T), got %v (%T)", path, expected, expected, value, value)
+		}
+	}
+}
+
+func TestGetSetErrors(t *testing.T) {
+	game := newTestGame(1162, 4, 10)
+	for _, path := range []string{
+		"",
+		"Troops[10].Gold",
+		"Troops[-1].Gold",
+		"Troops[0].Gold[0]",
+		"Troops[0].Golden",
+		"Troops[0.Gold",
+		"Troops[",
+		"Troops[0][",
+		"Troops[0].Proficiencies[]",
+		"Header.PlayerName.Chars",
+		"Quests[0].Notes[16]",
+	} {
+		if _, err := Get(game, path); err == nil {
+			t.Errorf("expected an error for path %q", path)
+		}
+	}
+
+	for _, set := range []struct {
+		path  string
+		value any
+	}{
+		{"Troops[0].Gold", "5000"},
+		{"Troops[0].Gold", int64(1) << 40},
+		{"Troops[0].Flags", -1},
+		{"Troops[0].Proficiencies[2]", true},
+		{"Header.PlayerName", 3},
+		{"Troops[0].Notes[0]", Int32(1)},
+		{"Troops[0].Gold", nil},
+	} {
+		if err := Set(&game, set.path, set.value); err == nil {
+			t.Errorf("expected an error setting %s to %#v", set.path, set.value)
+		}
+	}
+	if err := SetText(&game, "Troops[0].Gold", "lots"); err == nil {
+		t.Error("expected an error setting Troops[0].Gold to text that is not a number")
+	}
+	if err := SetText(&game, "Troops[0].Notes[0]", "note"); err == nil {
+		t.Error("expected an error setting a Note from text")
+	}
+}
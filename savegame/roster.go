@@ -0,0 +1,178 @@
+package savegame
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// PlayerParty edits the stacks of the player party, party 0, keeping
+// Game.PlayerPartyStackAdditionalInfo in line with them.
+//
+// Whether a stack has additional info depends on whether its troop is a
+// hero, which is decided by the options as for Encode.
+type PlayerParty struct {
+	game    *Game
+	options LoadOptions
+}
+
+// PlayerParty returns the player party of the game, whose options must be
+// those the game is saved with.
+func (game *Game) PlayerParty(options LoadOptions) PlayerParty {
+	return PlayerParty{game: game, options: options}
+}
+
+func (p PlayerParty) party() *Party {
+	return &p.game.PartyRecords[0].Party
+}
+
+// Stacks returns the stacks of the player party. They must not be modified.
+func (p PlayerParty) Stacks() []PartyStack {
+	return p.party().Stacks
+}
+
+// stackIndex returns the index of the member stack of troopId, or -1.
+// Prisoner stacks, which have Flags set, are never matched.
+func (p PlayerParty) stackIndex(troopId int) int {
+	return slices.IndexFunc(p.party().Stacks, func(stack PartyStack) bool {
+		return stack.Flags == 0 && int(stack.TroopId) == troopId
+	})
+}
+
+// numMembers returns the number of member stacks, which come before the
+// prisoner stacks.
+func (p PlayerParty) numMembers() int {
+	stacks := p.party().Stacks
+	if i := slices.IndexFunc(stacks, func(stack PartyStack) bool { return stack.Flags != 0 }); i >= 0 {
+		return i
+	}
+	return len(stacks)
+}
+
+// AddStack adds count troops of troopId, wounded of whom are wounded, to the
+// player party. They join the member stack of that troop if there is one, or
+// else form a new stack after the other members and before any prisoners,
+// whose troops are given random DNAs.
+func (p PlayerParty) AddStack(troopId int, count int, wounded int) error {
+	if troopId < 0 || troopId >= len(p.game.Troops) {
+		return fmt.Errorf("savegame: troop %d does not exist", troopId)
+	}
+	if count <= 0 || wounded < 0 || wounded > count {
+		return fmt.Errorf("savegame: cannot add %d troops of which %d are wounded", count, wounded)
+	}
+	party := p.party()
+	isHero := p.options.isHero(troopId)
+	if i := p.stackIndex(troopId); i >= 0 {
+		if isHero {
+			return fmt.Errorf("savegame: hero %d is already in the player party", troopId)
+		}
+		party.Stacks[i].NumTroops += Int32(count)
+		party.Stacks[i].NumWoundedTroops += Int32(wounded)
+		return nil
+	}
+	if isHero && count != 1 {
+		return fmt.Errorf("savegame: cannot add %d of hero %d", count, troopId)
+	}
+	i := p.numMembers()
+	party.Stacks = slices.Insert(party.Stacks, i, PartyStack{
+		TroopId:          Int32(troopId),
+		NumTroops:        Int32(count),
+		NumWoundedTroops: Int32(wounded),
+	})
+	p.game.PlayerPartyStackAdditionalInfo = slices.Insert(p.game.PlayerPartyStackAdditionalInfo, i, PlayerPartyStack{})
+	p.sync()
+	return nil
+}
+
+// RemoveStack removes the member stack of troopId from the player party. The stack
+// of the player cannot be removed.
+func (p PlayerParty) RemoveStack(troopId int) error {
+	i, err := p.editableStackIndex(troopId)
+	if err != nil {
+		return err
+	}
+	party := p.party()
+	party.Stacks = slices.Delete(party.Stacks, i, i+1)
+	p.game.PlayerPartyStackAdditionalInfo = slices.Delete(p.game.PlayerPartyStackAdditionalInfo, i, i+1)
+	p.sync()
+	return nil
+}
+
+// SetStackCount sets the number of troops and wounded troops in the member
+// stack of troopId, removing the stack if count is 0. Upgradeable troops are limited
+// to the new count.
+func (p PlayerParty) SetStackCount(troopId int, count int, wounded int) error {
+	if count < 0 || wounded < 0 || wounded > count {
+		return fmt.Errorf("savegame: cannot have %d troops of which %d are wounded", count, wounded)
+	}
+	if count == 0 {
+		return p.RemoveStack(troopId)
+	}
+	i, err := p.editableStackIndex(troopId)
+	if err != nil {
+		return err
+	}
+	if count != 1 && p.options.isHero(troopId) {
+		return fmt.Errorf("savegame: cannot have %d of hero %d", count, troopId)
+	}
+	stack := &p.party().Stacks[i]
+	stack.NumTroops = Int32(count)
+	stack.NumWoundedTroops = Int32(wounded)
+	info := &p.game.PlayerPartyStackAdditionalInfo[i]
+	info.NumUpgradeable = min(info.NumUpgradeable, Int32(count))
+	return nil
+}
+
+// MoveStack moves the member stack of troopId to index, shifting the stacks in
+// between. The stack of the player always comes first, and members always
+// come before prisoners.
+func (p PlayerParty) MoveStack(troopId int, index int) error {
+	from, err := p.editableStackIndex(troopId)
+	if err != nil {
+		return err
+	}
+	party := p.party()
+	if numMembers := p.numMembers(); index < 1 || index >= numMembers {
+		return fmt.Errorf("savegame: cannot move a stack to index %d of %d members", index, numMembers)
+	}
+	stack := party.Stacks[from]
+	party.Stacks = slices.Insert(slices.Delete(party.Stacks, from, from+1), index, stack)
+	info := p.game.PlayerPartyStackAdditionalInfo[from]
+	additionalInfo := slices.Delete(p.game.PlayerPartyStackAdditionalInfo, from, from+1)
+	p.game.PlayerPartyStackAdditionalInfo = slices.Insert(additionalInfo, index, info)
+	p.sync()
+	return nil
+}
+
+func (p PlayerParty) editableStackIndex(troopId int) (int, error) {
+	if troopId == 0 {
+		return -1, fmt.Errorf("savegame: the stack of the player cannot be changed")
+	}
+	i := p.stackIndex(troopId)
+	if i < 0 {
+		return -1, fmt.Errorf("savegame: troop %d is not a member of the player party", troopId)
+	}
+	return i, nil
+}
+
+// sync brings the additional info in line with the stacks after they were
+// added, removed or moved, as Decode would have read it: heroes have none,
+// and only the first 32 stacks have troop DNAs. A stack that moves into the
+// first 32 without DNAs is given random ones.
+func (p PlayerParty) sync() {
+	party := p.party()
+	party.NumStacks = Int32(len(party.Stacks))
+	for i := range party.Stacks {
+		info := &p.game.PlayerPartyStackAdditionalInfo[i]
+		switch {
+		case !hasAdditionalInfo(*party, i, p.options):
+			*info = PlayerPartyStack{}
+		case i >= 32:
+			info.TroopDnas = [32]Int32{}
+		case info.TroopDnas == [32]Int32{}:
+			for j := range info.TroopDnas {
+				info.TroopDnas[j] = Int32(rand.Int32())
+			}
+		}
+	}
+}
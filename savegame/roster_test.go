@@ -0,0 +1,181 @@
+package savegame
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func stackTroopIds(game Game) []Int32 {
+	var troopIds []Int32
+	for _, stack := range game.PartyRecords[0].Party.Stacks {
+		troopIds = append(troopIds, stack.TroopId)
+	}
+	return troopIds
+}
+
+func requireRoundTrip(t *testing.T, game Game, options LoadOptions) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Encode(&buf, game, SaveOptions{LoadOptions: options}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(&buf, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.PartyRecords[0], game.PartyRecords[0]) ||
+		!reflect.DeepEqual(decoded.PlayerPartyStackAdditionalInfo, game.PlayerPartyStackAdditionalInfo) {
+		t.Error("player party was different after encoding and decoding")
+	}
+}
+
+func TestPlayerParty(t *testing.T) {
+	game := newTestGame(1162, 4, 10)
+	playerParty := game.PlayerParty(LoadOptions{})
+	if err := playerParty.AddStack(3, 5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := playerParty.AddStack(1, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := playerParty.SetStackCount(2, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	stacks := playerParty.Stacks()
+	if stacks[1].NumTroops != 12 || stacks[1].NumWoundedTroops != 1 {
+		t.Errorf("expected 12 troops of which 1 wounded in the merged stack, got %+v", stacks[1])
+	}
+	if upgradeable := game.PlayerPartyStackAdditionalInfo[2].NumUpgradeable; upgradeable != 1 {
+		t.Errorf("expected upgradeable troops to be limited to 1, got %d", upgradeable)
+	}
+	dnas := game.PlayerPartyStackAdditionalInfo[3].TroopDnas
+	if dnas == [32]Int32{} {
+		t.Error("expected the new stack to have troop DNAs")
+	}
+	requireRoundTrip(t, game, LoadOptions{})
+
+	if err := playerParty.MoveStack(3, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := playerParty.RemoveStack(1); err != nil {
+		t.Fatal(err)
+	}
+	if troopIds := stackTroopIds(game); !reflect.DeepEqual(troopIds, []Int32{0, 3, 2}) {
+		t.Errorf("expected stacks of troops [0 3 2], got %v", troopIds)
+	}
+	if game.PartyRecords[0].Party.NumStacks != 3 || len(game.PlayerPartyStackAdditionalInfo) != 3 {
+		t.Errorf("expected 3 stacks with additional info, got NumStacks %d and %d",
+			game.PartyRecords[0].Party.NumStacks, len(game.PlayerPartyStackAdditionalInfo))
+	}
+	if game.PlayerPartyStackAdditionalInfo[1].TroopDnas != dnas {
+		t.Error("expected the moved stack to keep its troop DNAs")
+	}
+	requireRoundTrip(t, game, LoadOptions{})
+
+	for name, err := range map[string]error{
+		"removing the player":        playerParty.RemoveStack(0),
+		"adding a missing troop":     playerParty.AddStack(99, 1, 0),
+		"adding the player again":    playerParty.AddStack(0, 1, 0),
+		"adding more wounded":        playerParty.AddStack(4, 1, 2),
+		"counting a missing stack":   playerParty.SetStackCount(5, 1, 0),
+		"moving above the player":    playerParty.MoveStack(3, 0),
+		"moving past the last stack": playerParty.MoveStack(3, 3),
+	} {
+		if err == nil {
+			t.Errorf("expected an error %s", name)
+		}
+	}
+}
+
+func TestPlayerPartyTroopDnas(t *testing.T) {
+	game := newTestGame(1162, 1, 40)
+	playerParty := game.PlayerParty(LoadOptions{})
+	for troopId := 3; troopId < 40; troopId++ {
+		if err := playerParty.AddStack(troopId, 1, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dnas := game.PlayerPartyStackAdditionalInfo[32].TroopDnas; dnas != [32]Int32{} {
+		t.Error("expected no troop DNAs past the first 32 stacks")
+	}
+	requireRoundTrip(t, game, LoadOptions{})
+
+	if err := playerParty.MoveStack(39, 1); err != nil {
+		t.Fatal(err)
+	}
+	if dnas := game.PlayerPartyStackAdditionalInfo[1].TroopDnas; dnas == [32]Int32{} {
+		t.Error("expected a stack moved into the first 32 to be given troop DNAs")
+	}
+	if dnas := game.PlayerPartyStackAdditionalInfo[32].TroopDnas; dnas != [32]Int32{} {
+		t.Error("expected the stack moved out of the first 32 to lose its troop DNAs")
+	}
+	requireRoundTrip(t, game, LoadOptions{})
+}
+
+func TestPlayerPartyModuleHeroes(t *testing.T) {
+	troops := make([]module.Troop, 10)
+	troops[0].Flags = module.TfHero
+	troops[5].Flags = module.TfHero
+	options := LoadOptions{Module: &module.Module{Troops: troops}}
+	game := newTestGame(1162, 4, 10)
+	playerParty := game.PlayerParty(options)
+	if err := playerParty.AddStack(5, 5, 0); err == nil {
+		t.Error("expected an error adding 5 of a hero of the module")
+	}
+	if err := playerParty.AddStack(5, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if info := game.PlayerPartyStackAdditionalInfo[3]; info != (PlayerPartyStack{}) {
+		t.Errorf("expected no additional info for a hero of the module, got %+v", info)
+	}
+	if err := playerParty.SetStackCount(5, 2, 0); err == nil {
+		t.Error("expected an error counting 2 of a hero of the module")
+	}
+	requireRoundTrip(t, game, options)
+}
+
+func TestPlayerPartyPrisoners(t *testing.T) {
+	game := newTestGame(1162, 4, 10)
+	party := &game.PartyRecords[0].Party
+	party.Stacks = append(party.Stacks, PartyStack{TroopId: 3, NumTroops: 4, Flags: 1})
+	party.NumStacks++
+	game.PlayerPartyStackAdditionalInfo = append(game.PlayerPartyStackAdditionalInfo, PlayerPartyStack{})
+	playerParty := game.PlayerParty(LoadOptions{})
+	if err := playerParty.AddStack(3, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if troopIds := stackTroopIds(game); !reflect.DeepEqual(troopIds, []Int32{0, 1, 2, 3, 3}) {
+		t.Errorf("expected stacks of troops [0 1 2 3 3], got %v", troopIds)
+	}
+	if member, prisoner := party.Stacks[3], party.Stacks[4]; member.Flags != 0 || member.NumTroops != 2 ||
+		prisoner.Flags != 1 || prisoner.NumTroops != 4 {
+		t.Errorf("expected the new members before the untouched prisoners, got %+v and %+v", member, prisoner)
+	}
+	if err := playerParty.AddStack(3, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if party.Stacks[3].NumTroops != 3 || party.Stacks[4].NumTroops != 4 {
+		t.Errorf("expected the members to join the member stack, got %+v", party.Stacks)
+	}
+	if err := playerParty.SetStackCount(3, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if troopIds := stackTroopIds(game); !reflect.DeepEqual(troopIds, []Int32{0, 1, 2, 3}) || party.Stacks[3].Flags != 1 {
+		t.Errorf("expected only the prisoner stack of troop 3 to be left, got %+v", party.Stacks)
+	}
+	requireRoundTrip(t, game, LoadOptions{})
+
+	for name, err := range map[string]error{
+		"removing a prisoner stack":    playerParty.RemoveStack(3),
+		"counting a prisoner stack":    playerParty.SetStackCount(3, 1, 0),
+		"moving a prisoner stack":      playerParty.MoveStack(3, 1),
+		"moving a member past members": playerParty.MoveStack(1, 3),
+	} {
+		if err == nil {
+			t.Errorf("expected an error %s", name)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package savegame
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestRoundTrip(t *testing.T) {
+	regularTroopInventory := LoadOptions{Module: &module.Module{Ini: module.Ini{"dont_load_regular_troop_inventories": {"0"}}}}
+	for _, version := range testGameVersions {
+		for _, size := range []struct {
+			name       string
+			numParties int
+			numTroops  int
+		}{
+			{"minimal", 1, 1},
+			{"small", 12, 470},
+		} {
+			for _, options := range []struct {
+				name    string
+				options LoadOptions
+			}{
+				{"native", LoadOptions{}},
+				{"regular troop inventory", regularTroopInventory},
+			} {
+				t.Run(version.name+"/"+size.name+"/"+options.name, func(t *testing.T) {
+					game := newTestGame(version.version, size.numParties, size.numTroops)
+					data := game.write(options.options)
+					decoded, err := decode(data, options.options)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !reflect.DeepEqual(decoded, game) {
+						t.Error("game was different after writing and reading it")
+					}
+					if !bytes.Equal(decoded.write(options.options), data) {
+						t.Error("savegame was different after reading and writing it")
+					}
+				})
+			}
+		}
+	}
+}
+
+// Fields a version does not store must be neither written nor read.
+func TestRoundTripDropsFieldsOfOtherVersions(t *testing.T) {
+	for _, version := range testGameVersions {
+		t.Run(version.name, func(t *testing.T) {
+			game := newTestGame(version.version, 3, 3)
+			game.CombatDifficulty = 3
+			game.CombatSpeed = 3
+			party := &game.PartyRecords[1].Party
+			party.Marshall = 9
+			party.ExtraMapIconId = 9
+			party.ExtraMapIconFadeFrequency = 9
+			party.Unused2 = 9
+			decoded, err := decode(game.write(LoadOptions{}), LoadOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			decodedParty := decoded.PartyRecords[1].Party
+			capabilities, err := VersionCapabilities(version.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checks := []struct {
+				name     string
+				stored   bool
+				modified bool
+			}{
+				{"CombatDifficulty", capabilities.HasCombatSettings, decoded.CombatDifficulty == 3},
+				{"CombatSpeed", capabilities.HasCombatSettings, decoded.CombatSpeed == 3},
+				{"Marshall", capabilities.HasMarshall, decodedParty.Marshall == 9},
+				{"ExtraMapIconId", capabilities.HasExtraMapIcon, decodedParty.ExtraMapIconId == 9},
+				{"ExtraMapIconFadeFrequency", capabilities.HasExtraMapIcon, decodedParty.ExtraMapIconFadeFrequency == 9},
+				{"Unused2", capabilities.HasPartyUnused2, decodedParty.Unused2 == 9},
+			}
+			for _, check := range checks {
+				if check.stored != check.modified {
+					t.Errorf("expected %s to be stored: %t, was stored: %t", check.name, check.stored, check.modified)
+				}
+			}
+		})
+	}
+}
@@ -1,65 +1,54 @@
 package savegame
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
 	"os"
+	"path/filepath"
+	"runtime"
 )
 
 func (b *Bool) append(buf []byte) []byte {
-	buf, err := binary.Append(buf, binary.LittleEndian, b)
-	if err != nil {
-		panic(err)
+	if *b {
+		return append(buf, 1)
 	}
-	return buf
+	return append(buf, 0)
 }
 
 func (i *Int32) append(buf []byte) []byte {
-	buf, err := binary.Append(buf, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
-	return buf
+	return binary.LittleEndian.AppendUint32(buf, uint32(*i))
 }
 
 func (i *Int64) append(buf []byte) []byte {
-	buf, err := binary.Append(buf, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
-	return buf
+	return binary.LittleEndian.AppendUint64(buf, uint64(*i))
 }
 
 func (i *UInt32) append(buf []byte) []byte {
-	buf, err := binary.Append(buf, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
-	return buf
+	return binary.LittleEndian.AppendUint32(buf, uint32(*i))
 }
 
 func (i *UInt64) append(buf []byte) []byte {
-	buf, err := binary.Append(buf, binary.LittleEndian, i)
-	if err != nil {
-		panic(err)
-	}
-	return buf
+	return binary.LittleEndian.AppendUint64(buf, uint64(*i))
 }
 
 func (f *Float) append(buf []byte) []byte {
-	buf, err := binary.Append(buf, binary.LittleEndian, f)
-	if err != nil {
-		panic(err)
-	}
-	return buf
+	return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(*f)))
 }
 
 func (s *String) append(buf []byte) []byte {
-	buf = s.NumChars.append(buf)
-	buf, err := binary.Append(buf, binary.LittleEndian, s.Chars)
-	if err != nil {
-		panic(err)
-	}
-	return buf
+	buf = appendLength(buf, len(s.Chars))
+	return append(buf, s.Chars...)
+}
+
+// Length prefixes are derived from the slices they describe rather than from
+// the Num.* fields, so that lists can be edited without keeping those in sync.
+func appendLength(buf []byte, length int) []byte {
+	return binary.LittleEndian.AppendUint32(buf, uint32(length))
 }
 
 func (header *Header) append(buf []byte) []byte {
@@ -105,7 +94,7 @@ func (quest *Quest) append(buf []byte) []byte {
 	for i := 0; i < len(quest.Notes); i++ {
 		buf = quest.Notes[i].append(buf)
 	}
-	buf = quest.NumSlots.append(buf)
+	buf = appendLength(buf, len(quest.Slots))
 	for i := 0; i < len(quest.Slots); i++ {
 		buf = quest.Slots[i].append(buf)
 	}
@@ -120,7 +109,7 @@ func (infoPage *InfoPage) append(buf []byte) []byte {
 }
 
 func (site *Site) append(buf []byte) []byte {
-	buf = site.NumSlots.append(buf)
+	buf = appendLength(buf, len(site.Slots))
 	for i := 0; i < len(site.Slots); i++ {
 		buf = site.Slots[i].append(buf)
 	}
@@ -128,7 +117,7 @@ func (site *Site) append(buf []byte) []byte {
 }
 
 func (faction *Faction) append(buf []byte) []byte {
-	buf = faction.NumSlots.append(buf)
+	buf = appendLength(buf, len(faction.Slots))
 	for i := 0; i < len(faction.Slots); i++ {
 		buf = faction.Slots[i].append(buf)
 	}
@@ -159,7 +148,7 @@ func (partyTemplate *PartyTemplate) append(buf []byte) []byte {
 	buf = partyTemplate.NumPartiesCreated.append(buf)
 	buf = partyTemplate.NumPartiesDestroyed.append(buf)
 	buf = partyTemplate.NumPartiesDestroyedByPlayer.append(buf)
-	buf = partyTemplate.NumSlots.append(buf)
+	buf = appendLength(buf, len(partyTemplate.Slots))
 	for i := 0; i < len(partyTemplate.Slots); i++ {
 		buf = partyTemplate.Slots[i].append(buf)
 	}
@@ -174,7 +163,7 @@ func (partyStack *PartyStack) append(buf []byte) []byte {
 	return buf
 }
 
-func (party *Party) append(buf []byte, gameVersion Int32) []byte {
+func (party *Party) append(buf []byte, capabilities Capabilities) []byte {
 	buf = party.Id.append(buf)
 	buf = party.Name.append(buf)
 	buf = party.Flags.append(buf)
@@ -193,7 +182,7 @@ func (party *Party) append(buf []byte, gameVersion Int32) []byte {
 	buf = party.PositionX.append(buf)
 	buf = party.PositionY.append(buf)
 	buf = party.PositionZ.append(buf)
-	buf = party.NumStacks.append(buf)
+	buf = appendLength(buf, len(party.Stacks))
 	for i := 0; i < len(party.Stacks); i++ {
 		buf = party.Stacks[i].append(buf)
 	}
@@ -208,12 +197,12 @@ func (party *Party) append(buf []byte, gameVersion Int32) []byte {
 	buf = party.Helpfulness.append(buf)
 	buf = party.LabelVisible.append(buf)
 	buf = party.BanditAttraction.append(buf)
-	if (gameVersion >= 900 && gameVersion < 1000) || gameVersion >= 1020 {
+	if capabilities.HasMarshall {
 		buf = party.Marshall.append(buf)
 	}
 	buf = party.IgnorePlayerTimer.append(buf)
 	buf = party.BannerMapIconId.append(buf)
-	if gameVersion >= 1137 {
+	if capabilities.HasExtraMapIcon {
 		buf = party.ExtraMapIconId.append(buf)
 		buf = party.ExtraMapIconUpDownDistance.append(buf)
 		buf = party.ExtraMapIconUpDownFrequency.append(buf)
@@ -221,34 +210,34 @@ func (party *Party) append(buf []byte, gameVersion Int32) []byte {
 		buf = party.ExtraMapIconFadeFrequency.append(buf)
 	}
 	buf = party.AttachedToPartyId.append(buf)
-	if gameVersion >= 1162 {
+	if capabilities.HasPartyUnused2 {
 		buf = party.Unused2.append(buf)
 	}
 	buf = party.IsAttached.append(buf)
-	buf = party.NumAttachedPartyIds.append(buf)
+	buf = appendLength(buf, len(party.AttachedPartyIds))
 	for i := 0; i < len(party.AttachedPartyIds); i++ {
 		buf = party.AttachedPartyIds[i].append(buf)
 	}
-	buf = party.NumParticleSystemIds.append(buf)
+	buf = appendLength(buf, len(party.ParticleSystemIds))
 	for i := 0; i < len(party.ParticleSystemIds); i++ {
 		buf = party.ParticleSystemIds[i].append(buf)
 	}
 	for i := 0; i < len(party.Notes); i++ {
 		buf = party.Notes[i].append(buf)
 	}
-	buf = party.NumSlots.append(buf)
+	buf = appendLength(buf, len(party.Slots))
 	for i := 0; i < len(party.Slots); i++ {
 		buf = party.Slots[i].append(buf)
 	}
 	return buf
 }
 
-func (partyRecord *PartyRecord) append(buf []byte, gameVersion Int32) []byte {
+func (partyRecord *PartyRecord) append(buf []byte, capabilities Capabilities) []byte {
 	buf = partyRecord.Valid.append(buf)
 	if partyRecord.Valid == 1 {
 		buf = partyRecord.RawId.append(buf)
 		buf = partyRecord.Id.append(buf)
-		buf = partyRecord.Party.append(buf, gameVersion)
+		buf = partyRecord.Party.append(buf, capabilities)
 	}
 	return buf
 }
@@ -295,8 +284,8 @@ func (item *Item) append(buf []byte) []byte {
 	return buf
 }
 
-func (troop *Troop) append(buf []byte) []byte {
-	buf = troop.NumSlots.append(buf)
+func (troop *Troop) append(buf []byte, options LoadOptions) []byte {
+	buf = appendLength(buf, len(troop.Slots))
 	for i := 0; i < len(troop.Slots); i++ {
 		buf = troop.Slots[i].append(buf)
 	}
@@ -319,7 +308,7 @@ func (troop *Troop) append(buf []byte) []byte {
 	buf = troop.ProficiencyPoints.append(buf)
 	buf = troop.Level.append(buf)
 	isHero := troop.Flags&heroFlag != 0
-	if isHero || loadRegularTroopInventory {
+	if isHero || options.regularTroopInventory() {
 		buf = troop.Gold.append(buf)
 		buf = troop.Experience.append(buf)
 		buf = troop.Health.append(buf)
@@ -344,20 +333,22 @@ func (troop *Troop) append(buf []byte) []byte {
 }
 
 func (itemKind *ItemKind) append(buf []byte) []byte {
-	buf = itemKind.NumSlots.append(buf)
+	buf = appendLength(buf, len(itemKind.Slots))
 	for i := 0; i < len(itemKind.Slots); i++ {
 		buf = itemKind.Slots[i].append(buf)
 	}
 	return buf
 }
 
-func (game *Game) write() []byte {
+func (game *Game) write(options LoadOptions) []byte {
+	/* Encode has already rejected versions without known capabilities. */
+	capabilities, _ := VersionCapabilities(game.Header.GameVersion)
 	var buf []byte
 	buf = game.Header.append(buf)
 	buf = game.GameTime.append(buf)
 	buf = game.RandomSeed.append(buf)
 	buf = game.SaveMode.append(buf)
-	if game.Header.GameVersion >= 1137 {
+	if capabilities.HasCombatSettings {
 		buf = game.CombatDifficulty.append(buf)
 		buf = game.CombatDifficultyFriendlies.append(buf)
 		buf = game.ReduceCombatAi.append(buf)
@@ -399,66 +390,66 @@ func (game *Game) write() []byte {
 	for i := 0; i < len(game.ClassNames); i++ {
 		buf = game.ClassNames[i].append(buf)
 	}
-	buf = game.NumGlobalVariables.append(buf)
+	buf = appendLength(buf, len(game.GlobalVariables))
 	for i := 0; i < len(game.GlobalVariables); i++ {
 		buf = game.GlobalVariables[i].append(buf)
 	}
-	buf = game.NumTriggers.append(buf)
+	buf = appendLength(buf, len(game.Triggers))
 	for i := 0; i < len(game.Triggers); i++ {
 		buf = game.Triggers[i].append(buf)
 	}
-	buf = game.NumSimpleTriggers.append(buf)
+	buf = appendLength(buf, len(game.SimpleTriggers))
 	for i := 0; i < len(game.SimpleTriggers); i++ {
 		buf = game.SimpleTriggers[i].append(buf)
 	}
-	buf = game.NumQuests.append(buf)
+	buf = appendLength(buf, len(game.Quests))
 	for i := 0; i < len(game.Quests); i++ {
 		buf = game.Quests[i].append(buf)
 	}
-	buf = game.NumInfoPages.append(buf)
+	buf = appendLength(buf, len(game.InfoPages))
 	for i := 0; i < len(game.InfoPages); i++ {
 		buf = game.InfoPages[i].append(buf)
 	}
-	buf = game.NumSites.append(buf)
+	buf = appendLength(buf, len(game.Sites))
 	for i := 0; i < len(game.Sites); i++ {
 		buf = game.Sites[i].append(buf)
 	}
-	buf = game.NumFactions.append(buf)
+	buf = appendLength(buf, len(game.Factions))
 	for i := 0; i < len(game.Factions); i++ {
 		buf = game.Factions[i].append(buf)
 	}
-	buf = game.NumMapTracks.append(buf)
+	buf = appendLength(buf, len(game.MapTracks))
 	for i := 0; i < len(game.MapTracks); i++ {
 		buf = game.MapTracks[i].append(buf)
 	}
-	buf = game.NumPartyTemplates.append(buf)
+	buf = appendLength(buf, len(game.PartyTemplates))
 	for i := 0; i < len(game.PartyTemplates); i++ {
 		buf = game.PartyTemplates[i].append(buf)
 	}
-	buf = game.NumPartyRecords.append(buf)
+	buf = appendLength(buf, len(game.PartyRecords))
 	buf = game.NumPartiesCreated.append(buf)
 	for i := 0; i < len(game.PartyRecords); i++ {
-		buf = game.PartyRecords[i].append(buf, game.Header.GameVersion)
+		buf = game.PartyRecords[i].append(buf, capabilities)
 	}
 	playerParty := game.PartyRecords[0].Party
 	for i := 0; i < len(game.PlayerPartyStackAdditionalInfo); i++ {
-		if hasAdditionalInfo(playerParty, i) {
+		if hasAdditionalInfo(playerParty, i, options) {
 			buf = game.PlayerPartyStackAdditionalInfo[i].append(buf, i)
 		}
 	}
-	buf = game.NumMapEventRecords.append(buf)
+	buf = appendLength(buf, len(game.MapEventRecords))
 	buf = game.NumMapEventsCreated.append(buf)
 	for i := 0; i < len(game.MapEventRecords); i++ {
 		buf = game.MapEventRecords[i].append(buf)
 	}
-	buf = game.NumTroops.append(buf)
+	buf = appendLength(buf, len(game.Troops))
 	for i := 0; i < len(game.Troops); i++ {
-		buf = game.Troops[i].append(buf)
+		buf = game.Troops[i].append(buf, options)
 	}
 	for i := 0; i < len(game.Unused5); i++ {
 		buf = game.Unused5[i].append(buf)
 	}
-	buf = game.NumItemKinds.append(buf)
+	buf = appendLength(buf, len(game.ItemKinds))
 	for i := 0; i < len(game.ItemKinds); i++ {
 		buf = game.ItemKinds[i].append(buf)
 	}
@@ -471,12 +462,131 @@ func (game *Game) write() []byte {
 	return buf
 }
 
-func Save(game Game, path string) (err error) {
-	file, err := os.Create(path)
+func Encode(w io.Writer, game Game, options SaveOptions) (err error) {
+	_, err = VersionCapabilities(game.Header.GameVersion)
+	if err != nil {
+		return fmt.Errorf("savegame: %w", err)
+	}
+	if issues := validate(&game, !options.SkipValidation); len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	buf := game.write(options.LoadOptions)
+	_, err = w.Write(buf)
+	return err
+}
+
+// Save writes the game to path without ever leaving a partly written file
+// there: it is written to a temporary file in the same directory, synced and
+// then renamed over path. With options.Backups set, the file being replaced is
+// kept as a backup first, so that path exists throughout.
+func Save(game Game, path string, options SaveOptions) (err error) {
+	var buf bytes.Buffer
+	err = Encode(&buf, game, options)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+	if _, err = file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err = file.Chmod(mode); err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if options.Backups > 0 {
+		if err = rotateBackups(path, options.Backups); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// BackupPath is the path of the nth most recent backup of path, counting from
+// 1, e.g. "sg00.sav.bak.1".
+func BackupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+// rotateBackups shifts the backups of path up by one, dropping the oldest, and
+// makes path itself the first backup by linking or copying it, leaving path
+// in place. Backups that do not exist are skipped, as is path if it does not
+// exist yet.
+func rotateBackups(path string, backups int) error {
+	err := os.Remove(BackupPath(path, backups))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for n := backups - 1; n >= 1; n-- {
+		err = os.Rename(BackupPath(path, n), BackupPath(path, n+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if _, err = os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	/* Not every file system supports hard links. */
+	if os.Link(path, BackupPath(path, 1)) == nil {
+		return nil
+	}
+	return copyFile(path, BackupPath(path, 1))
+}
+
+func copyFile(from string, to string) (err error) {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err = io.Copy(out, in); err != nil {
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// syncDir makes a rename in dir durable. Directories cannot be synced on
+// Windows, where this is left to the file system.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	buf := game.write()
-	return binary.Write(file, binary.LittleEndian, buf)
+	defer d.Close()
+	return d.Sync()
 }
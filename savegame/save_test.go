@@ -0,0 +1,169 @@
+package savegame
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestEncodeDerivesLengths(t *testing.T) {
+	game := newTestGame(1162, 4, 10)
+	party := &game.PartyRecords[1].Party
+	party.Stacks = append(party.Stacks, PartyStack{TroopId: 3, NumTroops: 4})
+	party.Name.Chars = []byte("Renamed Party")
+	game.Quests = append(game.Quests, game.Quests[0])
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, game, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(&buf, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedParty := decoded.PartyRecords[1].Party
+	if decodedParty.NumStacks != 3 || len(decodedParty.Stacks) != 3 {
+		t.Errorf("expected 3 stacks, got NumStacks %d and %d stacks", decodedParty.NumStacks, len(decodedParty.Stacks))
+	}
+	if decodedParty.Name.String() != "Renamed Party" {
+		t.Errorf("expected party name %q, got %q", "Renamed Party", decodedParty.Name)
+	}
+	if decoded.NumQuests != 2 {
+		t.Errorf("expected 2 quests, got %d", decoded.NumQuests)
+	}
+}
+
+func TestEncodeRejectsImpliedLengthMismatch(t *testing.T) {
+	game := newTestGame(1162, 4, 10)
+	game.Factions = append(game.Factions, game.Factions[0])
+	if err := Encode(&bytes.Buffer{}, game, SaveOptions{}); err == nil {
+		t.Error("expected an error for relations not matching the number of factions")
+	}
+
+	game = newTestGame(1162, 4, 10)
+	playerParty := &game.PartyRecords[0].Party
+	playerParty.Stacks = append(playerParty.Stacks, PartyStack{TroopId: 3, NumTroops: 1})
+	if err := Encode(&bytes.Buffer{}, game, SaveOptions{}); err == nil {
+		t.Error("expected an error for player party stacks without additional info")
+	}
+}
+
+func TestEncodeRejectsUnsupportedVersion(t *testing.T) {
+	game := newTestGame(MaxGameVersion, 1, 1)
+	game.Header.GameVersion = MaxGameVersion + 1
+	err := Encode(&bytes.Buffer{}, game, SaveOptions{})
+	var versionErr *UnsupportedVersionError
+	if !errors.As(err, &versionErr) {
+		t.Errorf("expected *UnsupportedVersionError, got %v", err)
+	}
+}
+
+func TestSaveBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sg00.sav")
+	game := newTestGame(1162, 4, 10)
+	for year := 1; year <= 4; year++ {
+		game.Year = Int32(year)
+		if err := Save(game, path, SaveOptions{Backups: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for n, year := range map[int]Int32{0: 4, 1: 3, 2: 2} {
+		p := path
+		if n > 0 {
+			p = BackupPath(path, n)
+		}
+		saved, err := Load(p, LoadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.Year != year {
+			t.Errorf("expected %s to be from year %d, got %d", filepath.Base(p), year, saved.Year)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected the savegame and 2 backups, got %d files", len(entries))
+	}
+}
+
+func TestSaveKeepsFileOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sg00.sav")
+	game := newTestGame(1162, 4, 10)
+	if err := Save(game, path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game.PartyRecords[1].Party.FactionId = 99
+	if err := Save(game, path, SaveOptions{Backups: 1}); err == nil {
+		t.Fatal("expected a validation error")
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(saved, original) {
+		t.Error("savegame changed after a failed save")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the savegame, got %d files", len(entries))
+	}
+}
+
+func TestRegularTroopInventory(t *testing.T) {
+	options := LoadOptions{Module: &module.Module{Ini: module.Ini{"dont_load_regular_troop_inventories": {"0"}}}}
+	game := newTestGame(1162, 4, 10)
+	game.Troops[1].Gold = 250
+	game.Troops[1].EquippedItems[0].ItemKindId = 12
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, game, SaveOptions{LoadOptions: options}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(&buf, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Troops[1], game.Troops[1]) {
+		t.Errorf("expected regular troop %+v, got %+v", game.Troops[1], decoded.Troops[1])
+	}
+}
+
+func TestModuleHeroes(t *testing.T) {
+	troops := make([]module.Troop, 10)
+	troops[0].Flags = module.TfHero
+	troops[2].Flags = module.TfHero
+	options := LoadOptions{Module: &module.Module{Ini: module.Ini{"dont_load_regular_troop_inventories": {"1"}}, Troops: troops}}
+	game := newTestGame(1162, 4, 10)
+	game.PlayerPartyStackAdditionalInfo[2] = PlayerPartyStack{}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, game, SaveOptions{LoadOptions: options}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(&buf, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, game) {
+		t.Error("game was different after encoding and decoding with module heroes")
+	}
+}
@@ -0,0 +1,153 @@
+package savegame
+
+import "fmt"
+
+const (
+	// See skl_.* in ID_skills.py and header_skills.py
+	SkillTrade = iota
+	SkillLeadership
+	SkillPrisonerManagement
+	_SkillReserved1
+	_SkillReserved2
+	_SkillReserved3
+	_SkillReserved4
+	SkillPersuasion
+	SkillEngineer
+	SkillFirstAid
+	SkillSurgery
+	SkillWoundTreatment
+	SkillInventoryManagement
+	SkillSpotting
+	SkillPathfinding
+	SkillTactics
+	SkillTracking
+	SkillTrainer
+	_SkillReserved5
+	_SkillReserved6
+	_SkillReserved7
+	_SkillReserved8
+	SkillLooting
+	SkillHorseArchery
+	SkillRiding
+	SkillAthletics
+	SkillShield
+	SkillWeaponMaster
+	_SkillReserved9
+	_SkillReserved10
+	_SkillReserved11
+	_SkillReserved12
+	_SkillReserved13
+	SkillPowerDraw
+	SkillPowerThrow
+	SkillPowerStrike
+	SkillIronflesh
+	_SkillReserved14
+	_SkillReserved15
+	_SkillReserved16
+	_SkillReserved17
+	_SkillReserved18
+	NumSkills
+)
+
+var nativeSkillNames = [NumSkills]string{
+	SkillTrade:               "Trade",
+	SkillLeadership:          "Leadership",
+	SkillPrisonerManagement:  "Prisoner Management",
+	SkillPersuasion:          "Persuasion",
+	SkillEngineer:            "Engineer",
+	SkillFirstAid:            "First Aid",
+	SkillSurgery:             "Surgery",
+	SkillWoundTreatment:      "Wound Treatment",
+	SkillInventoryManagement: "Inventory Management",
+	SkillSpotting:            "Spotting",
+	SkillPathfinding:         "Path-finding",
+	SkillTactics:             "Tactics",
+	SkillTracking:            "Tracking",
+	SkillTrainer:             "Trainer",
+	SkillLooting:             "Looting",
+	SkillHorseArchery:        "Horse Archery",
+	SkillRiding:              "Riding",
+	SkillAthletics:           "Athletics",
+	SkillShield:              "Shield",
+	SkillWeaponMaster:        "Weapon Master",
+	SkillPowerDraw:           "Power Draw",
+	SkillPowerThrow:          "Power Throw",
+	SkillPowerStrike:         "Power Strike",
+	SkillIronflesh:           "Ironflesh",
+}
+
+/* Native's skills.txt gives every skill the same maximum level. */
+const nativeSkillMaxLevel = 10
+
+// Skills are packed into Troop.Skills four bits each, eight to a word, so a
+// level can never exceed 15 whatever the module allows.
+const (
+	skillBits     = 4
+	skillsPerWord = 32 / skillBits
+	skillMask     = 1<<skillBits - 1
+)
+
+// SkillName returns the name of a skill in the module's skills.txt, or in
+// Native. Unused skills have no name.
+func (options LoadOptions) SkillName(skillId int) string {
+	if options.Module != nil && len(options.Module.Skills) > 0 {
+		if skill, ok := options.Module.Skill(skillId); ok {
+			return skill.Name
+		}
+		return ""
+	}
+	if skillId < 0 || skillId >= NumSkills {
+		return ""
+	}
+	return nativeSkillNames[skillId]
+}
+
+// SkillMaxLevel returns the highest level of a skill in the module's
+// skills.txt, or in Native.
+func (options LoadOptions) SkillMaxLevel(skillId int) int {
+	if options.Module != nil {
+		if skill, ok := options.Module.Skill(skillId); ok {
+			return min(skill.MaxLevel, skillMask)
+		}
+	}
+	return nativeSkillMaxLevel
+}
+
+func (troop Troop) Skill(skillId int) int {
+	if skillId < 0 || skillId >= NumSkills {
+		return 0
+	}
+	word := troop.Skills[skillId/skillsPerWord]
+	return int(word>>(skillId%skillsPerWord*skillBits)) & skillMask
+}
+
+// SetSkill sets the level of a skill, which must be between 0 and the skill's
+// maximum level.
+func (troop *Troop) SetSkill(options LoadOptions, skillId int, level int) error {
+	if skillId < 0 || skillId >= NumSkills {
+		return fmt.Errorf("savegame: invalid skill id %d", skillId)
+	}
+	if maxLevel := options.SkillMaxLevel(skillId); level < 0 || level > maxLevel {
+		return fmt.Errorf("savegame: level %d of skill %d is not between 0 and %d", level, skillId, maxLevel)
+	}
+	shift := skillId % skillsPerWord * skillBits
+	word := &troop.Skills[skillId/skillsPerWord]
+	*word = *word&^(skillMask<<shift) | UInt32(level)<<shift
+	return nil
+}
+
+// NamedSkills returns the troop's skills with a level above 0, keyed by their
+// names.
+func (troop Troop) NamedSkills(options LoadOptions) map[string]int {
+	skills := make(map[string]int)
+	for skillId := 0; skillId < NumSkills; skillId++ {
+		if level := troop.Skill(skillId); level > 0 {
+			name := options.SkillName(skillId)
+			if name == "" {
+				name = fmt.Sprintf("skill %d", skillId)
+			}
+			skills[name] = level
+		}
+	}
+	return skills
+}
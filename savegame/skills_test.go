@@ -0,0 +1,44 @@
+package savegame
+
+import (
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestSkills(t *testing.T) {
+	var troop Troop
+	troop.Skills[1] = 0x00000050
+	if level := troop.Skill(SkillFirstAid); level != 5 {
+		t.Errorf("expected first aid 5, got %d", level)
+	}
+	if err := troop.SetSkill(LoadOptions{}, SkillIronflesh, 7); err != nil {
+		t.Fatal(err)
+	}
+	if troop.Skills[4] != 0x00070000 {
+		t.Errorf("expected ironflesh in bits 16-19 of the fifth word, got 0x%08x", troop.Skills[4])
+	}
+	if err := troop.SetSkill(LoadOptions{}, SkillFirstAid, 2); err != nil {
+		t.Fatal(err)
+	}
+	if troop.Skills[1] != 0x00000020 {
+		t.Errorf("expected setting first aid to leave other skills alone, got 0x%08x", troop.Skills[1])
+	}
+	if err := troop.SetSkill(LoadOptions{}, SkillRiding, 11); err == nil {
+		t.Error("expected an error for a level above Native's maximum")
+	}
+	if err := troop.SetSkill(LoadOptions{}, NumSkills, 1); err == nil {
+		t.Error("expected an error for an invalid skill id")
+	}
+	skills := troop.NamedSkills(LoadOptions{})
+	if len(skills) != 2 || skills["First Aid"] != 2 || skills["Ironflesh"] != 7 {
+		t.Errorf("unexpected named skills %v", skills)
+	}
+
+	skillsTxt := make([]module.Skill, NumSkills)
+	skillsTxt[SkillRiding] = module.Skill{Id: SkillRiding, StringId: "skl_riding", Name: "Riding", MaxLevel: 15}
+	options := LoadOptions{Module: &module.Module{Skills: skillsTxt}}
+	if err := troop.SetSkill(options, SkillRiding, 15); err != nil {
+		t.Errorf("expected the module to allow riding 15: %v", err)
+	}
+}
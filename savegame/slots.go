@@ -0,0 +1,119 @@
+package savegame
+
+import (
+	"fmt"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+var slotKindNames = map[module.SlotKind]string{
+	module.PartySlot:         "party",
+	module.PartyTemplateSlot: "party template",
+	module.TroopSlot:         "troop",
+	module.FactionSlot:       "faction",
+	module.QuestSlot:         "quest",
+	module.ItemSlot:          "item",
+	module.SceneSlot:         "scene",
+}
+
+// SlotIndex returns the index of the named slot in the module's
+// module_constants.py, or in Native's if the module does not name it.
+func (options LoadOptions) SlotIndex(kind module.SlotKind, name string) (index int, ok bool) {
+	if options.Module != nil {
+		if index, ok = options.Module.Slots.Index(kind, name); ok {
+			return index, true
+		}
+	}
+	return module.NativeSlots.Index(kind, name)
+}
+
+func slotIndex(options LoadOptions, kind module.SlotKind, name string) (int, error) {
+	index, ok := options.SlotIndex(kind, name)
+	if !ok {
+		return 0, fmt.Errorf("savegame: unknown %s slot %q", slotKindNames[kind], name)
+	}
+	return index, nil
+}
+
+/* The game reads slots that were never set as 0. */
+func getSlot(slots []Int64, options LoadOptions, kind module.SlotKind, name string) (Int64, error) {
+	index, err := slotIndex(options, kind, name)
+	if err != nil || index >= len(slots) {
+		return 0, err
+	}
+	return slots[index], nil
+}
+
+func setSlot(slots *[]Int64, numSlots *Int32, options LoadOptions, kind module.SlotKind, name string, value Int64) error {
+	index, err := slotIndex(options, kind, name)
+	if err != nil {
+		return err
+	}
+	for len(*slots) <= index {
+		*slots = append(*slots, 0)
+	}
+	(*slots)[index] = value
+	*numSlots = Int32(len(*slots))
+	return nil
+}
+
+// Slot returns the value of the named slot, e.g. "slot_town_lord", as
+// numbered by SlotIndex.
+func (party Party) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(party.Slots, options, module.PartySlot, name)
+}
+
+// SetSlot sets the value of the named slot, adding slots up to it if needed.
+func (party *Party) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&party.Slots, &party.NumSlots, options, module.PartySlot, name, value)
+}
+
+func (partyTemplate PartyTemplate) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(partyTemplate.Slots, options, module.PartyTemplateSlot, name)
+}
+
+func (partyTemplate *PartyTemplate) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&partyTemplate.Slots, &partyTemplate.NumSlots, options, module.PartyTemplateSlot, name, value)
+}
+
+func (troop Troop) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(troop.Slots, options, module.TroopSlot, name)
+}
+
+func (troop *Troop) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&troop.Slots, &troop.NumSlots, options, module.TroopSlot, name, value)
+}
+
+func (faction Faction) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(faction.Slots, options, module.FactionSlot, name)
+}
+
+func (faction *Faction) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&faction.Slots, &faction.NumSlots, options, module.FactionSlot, name, value)
+}
+
+func (quest Quest) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(quest.Slots, options, module.QuestSlot, name)
+}
+
+func (quest *Quest) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&quest.Slots, &quest.NumSlots, options, module.QuestSlot, name, value)
+}
+
+// Slot returns the value of the named slot_item_.* slot.
+func (itemKind ItemKind) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(itemKind.Slots, options, module.ItemSlot, name)
+}
+
+func (itemKind *ItemKind) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&itemKind.Slots, &itemKind.NumSlots, options, module.ItemSlot, name, value)
+}
+
+// Slot returns the value of the named slot_scene_.* slot.
+func (site Site) Slot(options LoadOptions, name string) (Int64, error) {
+	return getSlot(site.Slots, options, module.SceneSlot, name)
+}
+
+func (site *Site) SetSlot(options LoadOptions, name string, value Int64) error {
+	return setSlot(&site.Slots, &site.NumSlots, options, module.SceneSlot, name, value)
+}
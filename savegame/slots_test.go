@@ -0,0 +1,40 @@
+package savegame
+
+import (
+	"testing"
+
+	"github.com/analyticdan/mbw-savegame-editor/module"
+)
+
+func TestSlots(t *testing.T) {
+	game := newTestGame(MaxGameVersion, 3, 3)
+	party := &game.PartyRecords[1].Party
+	party.Slots[7] = 5
+	native := LoadOptions{}
+	if lord, err := party.Slot(native, "slot_town_lord"); err != nil || lord != 5 {
+		t.Errorf("expected Native slot_town_lord to be 5, got %d (%v)", lord, err)
+	}
+	if enterprise, err := party.Slot(native, "slot_center_player_enterprise"); err != nil || enterprise != 0 {
+		t.Errorf("expected a slot past the end to be 0, got %d (%v)", enterprise, err)
+	}
+	if err := party.SetSlot(native, "slot_center_player_enterprise", 1); err != nil {
+		t.Fatal(err)
+	}
+	if party.NumSlots != 138 || len(party.Slots) != 138 || party.Slots[137] != 1 {
+		t.Errorf("expected slots to grow to slot_center_player_enterprise, got %d slots", party.NumSlots)
+	}
+
+	options := LoadOptions{Module: &module.Module{Slots: module.Slots{module.PartySlot: {"slot_town_lord": 2}}}}
+	if lord, err := party.Slot(options, "slot_town_lord"); err != nil || lord != party.Slots[2] {
+		t.Errorf("expected the module's slot_town_lord, got %d (%v)", lord, err)
+	}
+	if enterprise, err := party.Slot(options, "slot_center_player_enterprise"); err != nil || enterprise != 1 {
+		t.Errorf("expected Native's slot_center_player_enterprise for a slot the module does not name, got %d (%v)", enterprise, err)
+	}
+	if _, err := party.Slot(options, "slot_party_unknown"); err == nil {
+		t.Error("expected an error for a slot neither the module nor Native names")
+	}
+	if err := party.SetSlot(options, "slot_party_unknown", 1); err == nil {
+		t.Error("expected an error setting a slot neither the module nor Native names")
+	}
+}
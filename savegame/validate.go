@@ -0,0 +1,141 @@
+package savegame
+
+import "fmt"
+
+// Issue is an inconsistency found by Validate.
+type Issue struct {
+	// Path is the model element at fault, e.g.
+	// "PartyRecords[412].Party.Stacks[3].TroopId".
+	Path    string
+	Message string
+}
+
+func (issue Issue) String() string {
+	return issue.Path + ": " + issue.Message
+}
+
+// ValidationError is returned by Encode and Save for a game that Validate
+// finds issues with.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Issues) == 1 {
+		return "savegame: " + e.Issues[0].String()
+	}
+	return fmt.Sprintf("savegame: %d issues, the first being %s", len(e.Issues), e.Issues[0])
+}
+
+type validator struct {
+	game   *Game
+	issues []Issue
+}
+
+func (v *validator) report(path string, format string, args ...any) {
+	v.issues = append(v.issues, Issue{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) checkPartyId(path string, partyId Int32) {
+	if partyId < 0 || int(partyId) >= len(v.game.PartyRecords) {
+		v.report(path, "party %d does not exist", partyId)
+	} else if v.game.PartyRecords[partyId].Valid != 1 {
+		v.report(path, "party %d has been removed", partyId)
+	}
+}
+
+func (v *validator) checkTroopId(path string, troopId Int32) {
+	if troopId < 0 || int(troopId) >= len(v.game.Troops) {
+		v.report(path, "troop %d does not exist", troopId)
+	}
+}
+
+/* Empty item slots have an ItemKindId of -1. */
+func (v *validator) checkItem(path string, item Item) {
+	if item.ItemKindId < -1 || int(item.ItemKindId) >= len(v.game.ItemKinds) {
+		v.report(path+".ItemKindId", "item kind %d does not exist", item.ItemKindId)
+	}
+}
+
+// checkLengths reports lists whose lengths are implied by other lists, as
+// these cannot be derived while writing.
+func (v *validator) checkLengths() {
+	game := v.game
+	if len(game.PartyRecords) == 0 {
+		v.report("PartyRecords", "missing player party record")
+		return
+	}
+	for i, faction := range game.Factions {
+		if len(faction.Relations) != len(game.Factions) {
+			v.report(fmt.Sprintf("Factions[%d].Relations", i), "has %d relations, expected one per faction (%d)",
+				len(faction.Relations), len(game.Factions))
+		}
+	}
+	playerParty := game.PartyRecords[0].Party
+	if len(game.PlayerPartyStackAdditionalInfo) != len(playerParty.Stacks) {
+		v.report("PlayerPartyStackAdditionalInfo", "has %d entries, expected one per player party stack (%d)",
+			len(game.PlayerPartyStackAdditionalInfo), len(playerParty.Stacks))
+	}
+}
+
+// checkReferences reports ids that refer to parties, troops, factions or item
+// kinds that do not exist.
+func (v *validator) checkReferences() {
+	game := v.game
+	for i, partyRecord := range game.PartyRecords {
+		if partyRecord.Valid != 1 {
+			continue
+		}
+		party := partyRecord.Party
+		path := fmt.Sprintf("PartyRecords[%d].Party", i)
+		// Parties without a faction have a FactionId of -1.
+		if party.FactionId < -1 || int(party.FactionId) >= len(game.Factions) {
+			v.report(path+".FactionId", "faction %d does not exist", party.FactionId)
+		}
+		for j, stack := range party.Stacks {
+			v.checkTroopId(fmt.Sprintf("%s.Stacks[%d].TroopId", path, j), stack.TroopId)
+		}
+		for j, attachedPartyId := range party.AttachedPartyIds {
+			v.checkPartyId(fmt.Sprintf("%s.AttachedPartyIds[%d]", path, j), attachedPartyId)
+		}
+	}
+	for i, mapEventRecord := range game.MapEventRecords {
+		if mapEventRecord.Valid != 1 {
+			continue
+		}
+		path := fmt.Sprintf("MapEventRecords[%d].MapEvent", i)
+		v.checkPartyId(path+".AttackerPartyId", mapEventRecord.MapEvent.AttackerPartyId)
+		v.checkPartyId(path+".DefenderPartyId", mapEventRecord.MapEvent.DefenderPartyId)
+	}
+	// Only heroes are sure to have their items stored; see Troop.read.
+	for i, troop := range game.Troops {
+		if troop.Flags&heroFlag == 0 {
+			continue
+		}
+		for j, item := range troop.InventoryItems {
+			v.checkItem(fmt.Sprintf("Troops[%d].InventoryItems[%d]", i, j), item)
+		}
+		for j, item := range troop.EquippedItems {
+			v.checkItem(fmt.Sprintf("Troops[%d].EquippedItems[%d]", i, j), item)
+		}
+	}
+}
+
+// Validate checks that the game is consistent: that the lists whose lengths
+// depend on each other agree, and that ids refer to parties, troops,
+// factions and item kinds that exist. Encode and Save refuse games with
+// issues unless told to skip validation.
+func Validate(game Game) []Issue {
+	return validate(&game, true)
+}
+
+// The lengths are always checked, as a game whose lengths disagree cannot be
+// written at all.
+func validate(game *Game, references bool) []Issue {
+	v := validator{game: game}
+	v.checkLengths()
+	if references && len(game.PartyRecords) > 0 {
+		v.checkReferences()
+	}
+	return v.issues
+}
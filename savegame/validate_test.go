@@ -0,0 +1,52 @@
+package savegame
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if issues := Validate(newTestGame(MaxGameVersion, 12, 470)); len(issues) != 0 {
+		t.Fatalf("expected a valid test game, got %v", issues)
+	}
+
+	game := newTestGame(MaxGameVersion, 12, 470)
+	game.PartyRecords[1].Party.Stacks[0].TroopId = 470
+	game.PartyRecords[2].Party.FactionId = 4
+	game.PartyRecords[3].Party.AttachedPartyIds = []Int32{6}
+	game.MapEventRecords[0].MapEvent.DefenderPartyId = 12
+	game.Troops[200].EquippedItems[5].ItemKindId = 3
+	game.Factions[1].Relations = game.Factions[1].Relations[1:]
+	game.PlayerPartyStackAdditionalInfo = nil
+	var paths []string
+	for _, issue := range Validate(game) {
+		paths = append(paths, issue.Path)
+	}
+	expected := []string{
+		"Factions[1].Relations",
+		"PlayerPartyStackAdditionalInfo",
+		"PartyRecords[1].Party.Stacks[0].TroopId",
+		"PartyRecords[2].Party.FactionId",
+		"PartyRecords[3].Party.AttachedPartyIds[0]",
+		"MapEventRecords[0].MapEvent.DefenderPartyId",
+		"Troops[200].EquippedItems[5].ItemKindId",
+	}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected issues at\n%v\ngot\n%v", expected, paths)
+	}
+}
+
+func TestEncodeValidates(t *testing.T) {
+	game := newTestGame(MaxGameVersion, 4, 10)
+	game.PartyRecords[1].Party.Stacks[0].TroopId = 10
+	err := Encode(&bytes.Buffer{}, game, SaveOptions{})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Issues) != 1 {
+		t.Fatalf("expected a *ValidationError with one issue, got %v", err)
+	}
+	if err := Encode(&bytes.Buffer{}, game, SaveOptions{SkipValidation: true}); err != nil {
+		t.Errorf("expected SkipValidation to save the game: %v", err)
+	}
+}
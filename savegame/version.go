@@ -0,0 +1,68 @@
+package savegame
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotWarband is reported for data that does not start with the Warband
+// savegame magic number.
+var ErrNotWarband = errors.New("not a Warband savegame; original Mount&Blade saves are not supported")
+
+/* "WRDR" read as a little-endian Int32. */
+const magicNumber = 0x52445257
+
+// MaxGameVersion is the newest game version whose layout is known. Warband
+// 1.174 is the last patch; its savegames have the same layout as 1.162's.
+const MaxGameVersion = 1174
+
+// Capabilities lists the fields whose presence depends on the game version.
+type Capabilities struct {
+	// HasMarshall is set for Party.Marshall.
+	HasMarshall bool
+	// HasCombatSettings is set for the combat difficulty, AI and speed
+	// settings of Game.
+	HasCombatSettings bool
+	// HasExtraMapIcon is set for the Party.ExtraMapIcon fields.
+	HasExtraMapIcon bool
+	// HasPartyUnused2 is set for Party.Unused2.
+	HasPartyUnused2 bool
+}
+
+/* Each entry applies from its version up to the next entry's. */
+var versionCapabilities = []struct {
+	version      Int32
+	capabilities Capabilities
+}{
+	{0, Capabilities{}},
+	{900, Capabilities{HasMarshall: true}},
+	{1000, Capabilities{}},
+	{1020, Capabilities{HasMarshall: true}},
+	{1137, Capabilities{HasMarshall: true, HasCombatSettings: true, HasExtraMapIcon: true}},
+	{1162, Capabilities{HasMarshall: true, HasCombatSettings: true, HasExtraMapIcon: true, HasPartyUnused2: true}},
+}
+
+// UnsupportedVersionError is reported for a game version whose layout is not
+// known.
+type UnsupportedVersionError struct {
+	Version Int32
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("unsupported game version %d", e.Version)
+}
+
+// VersionCapabilities returns the capabilities of savegames of the given game
+// version, as stored in Header.GameVersion.
+func VersionCapabilities(gameVersion Int32) (Capabilities, error) {
+	if gameVersion < 0 || gameVersion > MaxGameVersion {
+		return Capabilities{}, &UnsupportedVersionError{gameVersion}
+	}
+	var capabilities Capabilities
+	for _, entry := range versionCapabilities {
+		if gameVersion >= entry.version {
+			capabilities = entry.capabilities
+		}
+	}
+	return capabilities, nil
+}